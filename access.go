@@ -6,6 +6,7 @@ package uplink
 import (
 	"context"
 	"errors"
+	"net"
 	"strings"
 	"time"
 	_ "unsafe" // for go:linkname
@@ -18,6 +19,7 @@ import (
 	"storj.io/common/paths"
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
+	"storj.io/uplink/private/dnsaccess"
 	"storj.io/uplink/private/metaclient"
 )
 
@@ -119,6 +121,23 @@ func ParseAccess(access string) (*Access, error) {
 	}, nil
 }
 
+// ParseAccessFromDNS parses the access grant published in the DNS TXT records
+// of host, in the format used for custom domains with linksharing. This
+// allows resolving storj://host/bucket/key references.
+//
+// The records are looked up on the txt-<host> subdomain and contain either a
+// "storj-access:<grant>" record or numbered "storj-grant-NN:<part>" records.
+// Access key IDs registered with the auth service are not supported.
+func ParseAccessFromDNS(ctx context.Context, host string) (_ *Access, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	serialized, err := dnsaccess.Lookup(ctx, net.DefaultResolver, host)
+	if err != nil {
+		return nil, packageError.Wrap(err)
+	}
+	return ParseAccess(serialized)
+}
+
 // SatelliteAddress returns the satellite node URL for this access grant.
 func (access *Access) SatelliteAddress() string {
 	return access.satelliteURL.String()
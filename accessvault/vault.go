@@ -0,0 +1,302 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package accessvault stores access grants in a local file, encrypted with a
+// passphrase.
+//
+// The encryption key is derived from the passphrase with scrypt and every
+// access grant is sealed with NaCl secretbox, so the file reveals only the
+// names of the stored access grants.
+package accessvault
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/zeebo/errs"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"storj.io/uplink"
+)
+
+// Error is the error class for this package.
+var Error = errs.Class("accessvault")
+
+// ErrAccessNotFound is returned when the vault has no access grant with the
+// requested name.
+var ErrAccessNotFound = errors.New("access grant not found")
+
+// ErrInvalidPassphrase is returned when the vault was created with a
+// different passphrase.
+var ErrInvalidPassphrase = errors.New("invalid passphrase")
+
+const (
+	fileVersion = 1
+
+	saltSize  = 32
+	nonceSize = 24
+	keySize   = 32
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// Vault is a file of named access grants, encrypted with a passphrase.
+type Vault struct {
+	path       string
+	passphrase []byte
+
+	mu   sync.Mutex
+	salt []byte
+	key  *[keySize]byte
+}
+
+// New returns a vault stored at path. The file is created on the first Save.
+func New(path string, passphrase []byte) *Vault {
+	return &Vault{
+		path:       path,
+		passphrase: append([]byte(nil), passphrase...),
+	}
+}
+
+// vaultFile is the serialized form of the vault.
+type vaultFile struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	// Check is an empty message sealed with the key, used to detect a wrong
+	// passphrase before anything is stored with it.
+	Check   []byte            `json:"check"`
+	Entries map[string][]byte `json:"entries"`
+}
+
+// Save encrypts and stores access under name, replacing any access grant
+// already stored with that name.
+func (vault *Vault) Save(name string, access *uplink.Access) error {
+	if name == "" {
+		return Error.New("name is empty")
+	}
+	serialized, err := access.Serialize()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	vault.mu.Lock()
+	defer vault.mu.Unlock()
+
+	file, key, err := vault.load(true)
+	if err != nil {
+		return err
+	}
+
+	file.Entries[name], err = seal(key, []byte(serialized))
+	if err != nil {
+		return err
+	}
+
+	return vault.store(file)
+}
+
+// Load decrypts the access grant stored under name.
+func (vault *Vault) Load(name string) (*uplink.Access, error) {
+	vault.mu.Lock()
+	defer vault.mu.Unlock()
+
+	file, key, err := vault.load(false)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, ok := file.Entries[name]
+	if !ok {
+		return nil, Error.Wrap(fmt.Errorf("%w: %q", ErrAccessNotFound, name))
+	}
+
+	serialized, err := open(key, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := uplink.ParseAccess(string(serialized))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return access, nil
+}
+
+// List returns the sorted names of the stored access grants.
+func (vault *Vault) List() ([]string, error) {
+	vault.mu.Lock()
+	defer vault.mu.Unlock()
+
+	file, _, err := vault.load(false)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(file.Entries))
+	for name := range file.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes the access grant stored under name.
+func (vault *Vault) Delete(name string) error {
+	vault.mu.Lock()
+	defer vault.mu.Unlock()
+
+	file, _, err := vault.load(false)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := file.Entries[name]; !ok {
+		return Error.Wrap(fmt.Errorf("%w: %q", ErrAccessNotFound, name))
+	}
+	delete(file.Entries, name)
+
+	return vault.store(file)
+}
+
+// load reads the vault file and derives its key. When the file does not
+// exist, it returns an empty vault, which gets a new salt when create is
+// set.
+func (vault *Vault) load(create bool) (*vaultFile, *[keySize]byte, error) {
+	data, err := os.ReadFile(vault.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		file := &vaultFile{
+			Version: fileVersion,
+			Entries: map[string][]byte{},
+		}
+		if !create {
+			return file, nil, nil
+		}
+
+		file.Salt = make([]byte, saltSize)
+		if _, err := rand.Read(file.Salt); err != nil {
+			return nil, nil, Error.Wrap(err)
+		}
+		key, err := vault.deriveKey(file.Salt)
+		if err != nil {
+			return nil, nil, err
+		}
+		file.Check, err = seal(key, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, key, nil
+	}
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, Error.New("invalid vault file: %v", err)
+	}
+	if file.Version != fileVersion {
+		return nil, nil, Error.New("unsupported vault version %d", file.Version)
+	}
+	if file.Entries == nil {
+		file.Entries = map[string][]byte{}
+	}
+
+	key, err := vault.deriveKey(file.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := open(key, file.Check); err != nil {
+		return nil, nil, Error.Wrap(ErrInvalidPassphrase)
+	}
+
+	return &file, key, nil
+}
+
+// store atomically replaces the vault file.
+func (vault *Vault) store(file *vaultFile) (err error) {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	dir := filepath.Dir(vault.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return Error.Wrap(err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(vault.path)+"-*.tmp")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, Error.Wrap(os.Remove(tmp.Name())))
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		return Error.Wrap(errs.Combine(err, tmp.Close()))
+	}
+	if err := tmp.Sync(); err != nil {
+		return Error.Wrap(errs.Combine(err, tmp.Close()))
+	}
+	if err := tmp.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(tmp.Name(), vault.path))
+}
+
+// deriveKey derives the key for salt from the passphrase. The last key is
+// remembered, since scrypt is slow on purpose.
+func (vault *Vault) deriveKey(salt []byte) (*[keySize]byte, error) {
+	if vault.key != nil && string(vault.salt) == string(salt) {
+		return vault.key, nil
+	}
+
+	derived, err := scrypt.Key(vault.passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var key [keySize]byte
+	copy(key[:], derived)
+
+	vault.salt = append([]byte(nil), salt...)
+	vault.key = &key
+	return &key, nil
+}
+
+// seal encrypts message with a random nonce, which is prepended to the
+// result.
+func seal(key *[keySize]byte, message []byte) ([]byte, error) {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return secretbox.Seal(nonce[:], message, &nonce, key), nil
+}
+
+// open decrypts a message sealed by seal.
+func open(key *[keySize]byte, sealed []byte) ([]byte, error) {
+	if len(sealed) < nonceSize {
+		return nil, Error.New("sealed message is too short")
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], sealed)
+
+	message, ok := secretbox.Open(nil, sealed[nonceSize:], &nonce, key)
+	if !ok {
+		return nil, Error.New("unable to decrypt")
+	}
+	return message, nil
+}
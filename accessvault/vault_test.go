@@ -0,0 +1,88 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accessvault_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+	"storj.io/uplink/accessvault"
+)
+
+func TestVault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault", "access.json")
+	vault := accessvault.New(path, []byte("passphrase"))
+
+	names, err := vault.List()
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	_, err = vault.Load("missing")
+	require.True(t, errors.Is(err, accessvault.ErrAccessNotFound))
+
+	first, second := newAccess(t), newAccess(t)
+	require.NoError(t, vault.Save("second", second))
+	require.NoError(t, vault.Save("first", first))
+
+	names, err = vault.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"first", "second"}, names)
+
+	// the access grants are not stored in plain text
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	serialized, err := first.Serialize()
+	require.NoError(t, err)
+	require.False(t, strings.Contains(string(data), serialized))
+
+	// a new vault for the same file can read the access grants
+	loaded, err := accessvault.New(path, []byte("passphrase")).Load("first")
+	require.NoError(t, err)
+	loadedSerialized, err := loaded.Serialize()
+	require.NoError(t, err)
+	require.Equal(t, serialized, loadedSerialized)
+
+	// a wrong passphrase is detected before anything is stored
+	wrong := accessvault.New(path, []byte("wrong"))
+	_, err = wrong.Load("first")
+	require.True(t, errors.Is(err, accessvault.ErrInvalidPassphrase))
+	require.True(t, errors.Is(wrong.Save("third", first), accessvault.ErrInvalidPassphrase))
+
+	require.NoError(t, vault.Delete("first"))
+	require.True(t, errors.Is(vault.Delete("first"), accessvault.ErrAccessNotFound))
+
+	names, err = vault.List()
+	require.NoError(t, err)
+	require.Equal(t, []string{"second"}, names)
+}
+
+func newAccess(t *testing.T) *uplink.Access {
+	apiKey, err := macaroon.NewAPIKey(testrand.Bytes(32))
+	require.NoError(t, err)
+
+	key := testrand.Key()
+	inner := grant.Access{
+		SatelliteAddress: "12EayRS2V1kEsWESU9QMRseFhdxYxKicsiFmxrsLZHeLUtdps3S@us1.storj.io:7777",
+		APIKey:           apiKey,
+		EncAccess:        grant.NewEncryptionAccessWithDefaultKey(&key),
+	}
+	inner.EncAccess.SetDefaultPathCipher(storj.EncAESGCM)
+
+	serialized, err := inner.Serialize()
+	require.NoError(t, err)
+
+	access, err := uplink.ParseAccess(serialized)
+	require.NoError(t, err)
+	return access
+}
@@ -145,6 +145,8 @@ func (project *Project) DeleteBucketWithObjects(ctx context.Context, bucket stri
 	}
 	defer func() { err = errs.Combine(err, db.Close()) }()
 
+	project.recentStats.forgetPrefix(bucket, "")
+
 	existing, err := db.DeleteBucket(ctx, bucket, true)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, "")
@@ -0,0 +1,184 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// bucketExportVersion is the version of the BucketExport format.
+const bucketExportVersion = 1
+
+// defaultImportWorkers is the default number of objects copied in parallel
+// by ImportBucketMetadata.
+const defaultImportWorkers = 4
+
+// BucketExport describes a bucket and optionally its objects. It can be
+// serialized as JSON to move a bucket between projects or satellites.
+type BucketExport struct {
+	Version int       `json:"version"`
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+
+	// Objects contains the objects of the bucket when they were requested
+	// with ExportBucketOptions.IncludeObjects.
+	Objects []ExportedObject `json:"objects,omitempty"`
+}
+
+// ExportedObject describes an object in a BucketExport.
+type ExportedObject struct {
+	Key           string         `json:"key"`
+	Created       time.Time      `json:"created"`
+	Expires       time.Time      `json:"expires,omitempty"`
+	ContentLength int64          `json:"content_length"`
+	Custom        CustomMetadata `json:"custom,omitempty"`
+}
+
+// ExportBucketOptions defines options for ExportBucketMetadata.
+type ExportBucketOptions struct {
+	// IncludeObjects includes the object keys, system and custom metadata in
+	// the export.
+	IncludeObjects bool
+}
+
+// ExportBucketMetadata returns the configuration of a bucket, and when
+// requested the metadata of all its objects. Object data is not included.
+func (project *Project) ExportBucketMetadata(ctx context.Context, bucket string, options *ExportBucketOptions) (export *BucketExport, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options == nil {
+		options = &ExportBucketOptions{}
+	}
+
+	info, err := project.StatBucket(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	export = &BucketExport{
+		Version: bucketExportVersion,
+		Name:    info.Name,
+		Created: info.Created,
+	}
+
+	if !options.IncludeObjects {
+		return export, nil
+	}
+
+	objects := project.ListObjects(ctx, bucket, &ListObjectsOptions{
+		Recursive: true,
+		System:    true,
+		Custom:    true,
+	})
+	for objects.Next() {
+		object := objects.Item()
+		export.Objects = append(export.Objects, ExportedObject{
+			Key:           object.Key,
+			Created:       object.System.Created,
+			Expires:       object.System.Expires,
+			ContentLength: object.System.ContentLength,
+			Custom:        object.Custom,
+		})
+	}
+	if err := objects.Err(); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ImportBucketOptions defines options for ImportBucketMetadata.
+type ImportBucketOptions struct {
+	// Bucket is the name of the imported bucket. When empty, the name of the
+	// exported bucket is used.
+	Bucket string
+
+	// Source is the project the bucket was exported from. When it's set, the
+	// exported objects are copied from it. Objects are copied on the
+	// satellite when Source is the importing project, otherwise they are
+	// downloaded from Source and uploaded again.
+	Source *Project
+	// Workers is the number of objects copied in parallel. Zero means 4.
+	Workers int
+}
+
+// ImportBucketMetadata creates the bucket described by export. When
+// options.Source is set, the exported objects are copied from the source
+// project. Objects that were removed from the source in the meantime are
+// skipped.
+func (project *Project) ImportBucketMetadata(ctx context.Context, export *BucketExport, options *ImportBucketOptions) (bucket *Bucket, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options == nil {
+		options = &ImportBucketOptions{}
+	}
+	if export == nil {
+		return nil, packageError.New("bucket export is nil")
+	}
+	if export.Version != bucketExportVersion {
+		return nil, packageError.New("unsupported bucket export version %d", export.Version)
+	}
+
+	name := options.Bucket
+	if name == "" {
+		name = export.Name
+	}
+
+	bucket, err = project.EnsureBucket(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Source == nil || len(export.Objects) == 0 {
+		return bucket, nil
+	}
+	if options.Source == project && name == export.Name {
+		// the objects are already there.
+		return bucket, nil
+	}
+
+	workers := options.Workers
+	if workers <= 0 {
+		workers = defaultImportWorkers
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+	for i := range export.Objects {
+		object := &export.Objects[i]
+		group.Go(func() error {
+			err := project.importObject(groupCtx, options.Source, export.Name, name, object)
+			if errors.Is(err, ErrObjectNotFound) {
+				return nil
+			}
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	return bucket, nil
+}
+
+// importObject copies a single exported object from source.
+func (project *Project) importObject(ctx context.Context, source *Project, sourceBucket, bucket string, object *ExportedObject) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if source == project {
+		_, err := project.CopyObject(ctx, sourceBucket, object.Key, bucket, object.Key, nil)
+		return err
+	}
+
+	_, err = reuploadObject(ctx, source, sourceBucket, &Object{
+		Key:    object.Key,
+		System: SystemMetadata{Expires: object.Expires},
+		Custom: object.Custom,
+	}, project, bucket, object.Key, nil)
+	return err
+}
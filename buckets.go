@@ -5,6 +5,7 @@ package uplink
 
 import (
 	"context"
+	"time"
 
 	"storj.io/uplink/private/metaclient"
 )
@@ -13,6 +14,10 @@ import (
 type ListBucketsOptions struct {
 	// Cursor sets the starting position of the iterator. The first item listed will be the one after the cursor.
 	Cursor string
+
+	// Timeout overrides Config.MetainfoTimeout for the requests of this
+	// listing.
+	Timeout time.Duration
 }
 
 // ListBuckets returns an iterator over the buckets.
@@ -22,6 +27,9 @@ func (project *Project) ListBuckets(ctx context.Context, options *ListBucketsOpt
 	if options == nil {
 		options = &ListBucketsOptions{}
 	}
+	if options.Timeout != 0 {
+		ctx = metaclient.WithTimeout(ctx, options.Timeout)
+	}
 
 	buckets := BucketIterator{
 		iterator: metaclient.IterateBuckets(ctx, metaclient.IterateBucketsOptions{
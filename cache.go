@@ -0,0 +1,126 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink/private/diskcache"
+	"storj.io/uplink/private/metaclient"
+)
+
+// cachedObject is an object whose content was found in the disk cache.
+type cachedObject struct {
+	file   *os.File
+	object metaclient.Object
+}
+
+// cacheKey returns the cache key of the content of object. The stream ID and
+// version change whenever the object is overwritten, so stale content is
+// never served.
+func (project *Project) cacheKey(bucket, key string, object *metaclient.Object) string {
+	return diskcache.Key(
+		project.access.satelliteURL.String(),
+		bucket, key,
+		hex.EncodeToString(object.Version),
+		hex.EncodeToString(object.Stream.ID),
+		strconv.FormatInt(object.Stream.Size, 10),
+	)
+}
+
+// openCachedObject opens the cached content of object. It returns nil when
+// the content is not cached.
+func (project *Project) openCachedObject(cacheKey string, object metaclient.Object) *cachedObject {
+	file, err := project.cache.Open(cacheKey)
+	if err != nil || file == nil {
+		// a broken cache must not fail downloads.
+		return nil
+	}
+	return &cachedObject{file: file, object: object}
+}
+
+// downloadFromCache sets up download to read the cached object.
+func (download *Download) downloadFromCache(project *Project, cached *cachedObject, opts metaclient.DownloadOptions) error {
+	object := cached.object
+	compression := objectCompression(object.Metadata)
+	if err := compression.checkRange(opts.Range); err != nil {
+		return errs.Combine(err, cached.file.Close())
+	}
+
+	download.object = convertObject(&object)
+	download.cacheFile = cached.file
+	download.tracker = project.tracker.Child("download", 1)
+
+	if compression != CompressionNone {
+		download.sizes.total = object.Size
+		download.decompress = newDecompressReader(cached.file, compression)
+		download.data = download.decompress
+		return nil
+	}
+
+	streamRange := opts.Range.Normalize(object.Size)
+	download.sizes.offset = streamRange.Start
+	download.sizes.length = streamRange.Limit - streamRange.Start
+	download.sizes.total = object.Size
+	download.data = io.NewSectionReader(cached.file, streamRange.Start, streamRange.Limit-streamRange.Start)
+	return nil
+}
+
+// cacheWhileReading returns a reader that stores the data read from the
+// download in the cache. The entry is committed once the whole object has
+// been read.
+func (project *Project) cacheWhileReading(download *Download, cacheKey string) io.Reader {
+	entry, err := project.cache.Create(cacheKey)
+	if err != nil {
+		mon.Event("cache_create_failed")
+		return download.data
+	}
+	download.cacheEntry = entry
+
+	return &cachingReader{
+		source:   download.data,
+		download: download,
+	}
+}
+
+// cachingReader copies everything read from source to the cache entry of
+// download.
+type cachingReader struct {
+	source   io.Reader
+	download *Download
+}
+
+// Read implements io.Reader.
+func (r *cachingReader) Read(p []byte) (n int, err error) {
+	n, err = r.source.Read(p)
+
+	entry := r.download.cacheEntry
+	if entry == nil {
+		return n, err
+	}
+
+	if n > 0 {
+		if _, werr := entry.Write(p[:n]); werr != nil {
+			mon.Event("cache_write_failed")
+			_ = entry.Abort()
+			r.download.cacheEntry = nil
+			return n, err
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		if cerr := entry.Commit(); cerr != nil {
+			mon.Event("cache_commit_failed")
+		}
+		r.download.cacheEntry = nil
+	}
+
+	return n, err
+}
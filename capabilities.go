@@ -0,0 +1,99 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink/private/metaclient"
+)
+
+// Support describes whether the satellite supports a feature.
+type Support int
+
+const (
+	// SupportUnknown means that the support couldn't be determined, e.g.
+	// because the access grant doesn't permit the request used to detect the
+	// feature, or because the satellite was unavailable.
+	SupportUnknown Support = iota
+	// Supported means that the satellite supports the feature.
+	Supported
+	// Unsupported means that the satellite doesn't support the feature.
+	Unsupported
+)
+
+// Capabilities describes the optional features supported by the satellite
+// of a project and the limits that apply to its uploads.
+//
+// The features are queried from the satellite. The satellite doesn't report
+// its limits, so the limits are the local defaults of the project.
+type Capabilities struct {
+	// Versioning is whether buckets can have versioning enabled.
+	Versioning Support
+	// ServerSideCopy is whether objects can be copied with CopyObject.
+	ServerSideCopy Support
+	// ServerSideMove is whether objects can be moved with MoveObject.
+	ServerSideMove Support
+	// Multipart is whether objects can be uploaded in parts with
+	// BeginUpload.
+	Multipart Support
+
+	// LocalSegmentSize is the size of the segments the project splits
+	// uploads into. It's configured locally, not by the satellite.
+	LocalSegmentSize int64
+	// DefaultMaxCustomMetadataSize is MaxCustomMetadataSize, the limit of
+	// satellites with the default configuration. The satellite of the
+	// project may be configured with a different limit.
+	DefaultMaxCustomMetadataSize int
+}
+
+// VerifyUploadOptions verifies options with the same local checks as
+// UploadObject, using LocalSegmentSize, so invalid options are detected
+// before an upload is started. It doesn't query the satellite.
+func (capabilities *Capabilities) VerifyUploadOptions(options *UploadOptions) error {
+	if options == nil {
+		return nil
+	}
+	return options.verify(capabilities.LocalSegmentSize)
+}
+
+// Capabilities queries the satellite for the optional features it supports,
+// so they can be detected before use instead of failing at runtime.
+func (project *Project) Capabilities(ctx context.Context) (_ *Capabilities, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	metainfoClient, err := project.dialMetainfoClient(ctx)
+	if err != nil {
+		return nil, packageError.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, metainfoClient.Close()) }()
+
+	capabilities, err := metainfoClient.GetCapabilities(ctx)
+	if err != nil {
+		return nil, packageError.Wrap(err)
+	}
+
+	return &Capabilities{
+		Versioning:     convertSupport(capabilities.Versioning),
+		ServerSideCopy: convertSupport(capabilities.ServerSideCopy),
+		ServerSideMove: convertSupport(capabilities.ServerSideMove),
+		Multipart:      convertSupport(capabilities.Multipart),
+
+		LocalSegmentSize:             project.segmentSize,
+		DefaultMaxCustomMetadataSize: MaxCustomMetadataSize,
+	}, nil
+}
+
+func convertSupport(support metaclient.Support) Support {
+	switch support {
+	case metaclient.Supported:
+		return Supported
+	case metaclient.Unsupported:
+		return Unsupported
+	default:
+		return SupportUnknown
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"runtime"
+
+	"golang.org/x/sys/cpu"
+
+	"storj.io/common/storj"
+)
+
+// ContentCipher selects the cipher used to encrypt the content of uploaded
+// objects. Downloads use the cipher the object was uploaded with.
+type ContentCipher int
+
+const (
+	// ContentCipherAESGCM uses AES-GCM. It's the default.
+	ContentCipherAESGCM ContentCipher = iota
+	// ContentCipherSecretBox uses XSalsa20-Poly1305 (SecretBox).
+	ContentCipherSecretBox
+	// ContentCipherAuto uses AES-GCM when the CPU supports hardware
+	// accelerated AES and XSalsa20-Poly1305 (SecretBox) otherwise.
+	ContentCipherAuto
+)
+
+// cipherSuite returns the cipher suite for the content cipher.
+func (cipher ContentCipher) cipherSuite() (storj.CipherSuite, error) {
+	switch cipher {
+	case ContentCipherAESGCM:
+		return storj.EncAESGCM, nil
+	case ContentCipherSecretBox:
+		return storj.EncSecretBox, nil
+	case ContentCipherAuto:
+		if hasHardwareAES() {
+			return storj.EncAESGCM, nil
+		}
+		return storj.EncSecretBox, nil
+	default:
+		return storj.EncUnspecified, packageError.New("unknown content cipher %d", cipher)
+	}
+}
+
+// hasHardwareAES returns whether AES-GCM is hardware accelerated by the Go
+// standard library on this CPU.
+func hasHardwareAES() bool {
+	switch runtime.GOARCH {
+	case "amd64":
+		return cpu.X86.HasAES && cpu.X86.HasPCLMULQDQ
+	case "arm64":
+		return cpu.ARM64.HasAES && cpu.ARM64.HasPMULL
+	case "s390x":
+		return cpu.S390X.HasAES && cpu.S390X.HasAESGCM
+	case "ppc64le":
+		// POWER8 introduced the vector AES and polynomial multiply
+		// instructions.
+		return cpu.PPC64.IsPOWER8
+	default:
+		return false
+	}
+}
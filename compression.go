@@ -0,0 +1,240 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink/private/metaclient"
+)
+
+// Compression is a compression scheme for object data.
+type Compression string
+
+const (
+	// CompressionNone stores object data as is.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses object data with gzip.
+	CompressionGzip Compression = "gzip"
+)
+
+// CompressionMetadataKey is the custom metadata key that records the
+// compression scheme of an object uploaded with UploadOptions.Compression.
+// Objects, whose data doesn't start like data compressed with the recorded
+// scheme, are downloaded as is.
+const CompressionMetadataKey = "uplink-compression"
+
+// compressionSampleSize is the amount of data that is compressed upfront to
+// decide whether the content is compressible.
+const compressionSampleSize = 64 * 1024
+
+// validate checks whether the compression scheme is known.
+func (compression Compression) validate() error {
+	switch compression {
+	case CompressionNone, CompressionGzip:
+		return nil
+	default:
+		return packageError.New("unknown compression %q", string(compression))
+	}
+}
+
+// newWriter returns a compressing writer for the scheme.
+func (compression Compression) newWriter(w io.Writer) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewWriterLevel(w, gzip.BestSpeed)
+	default:
+		return nil, packageError.New("unknown compression %q", string(compression))
+	}
+}
+
+// newReader returns a decompressing reader for the scheme.
+func (compression Compression) newReader(r io.Reader) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	default:
+		return nil, packageError.New("unknown compression %q", string(compression))
+	}
+}
+
+// compressWriter compresses data written to it, unless the first
+// compressionSampleSize bytes turn out to be incompressible.
+type compressWriter struct {
+	compression Compression
+	dst         io.Writer
+
+	sample  []byte
+	decided bool
+	enc     io.WriteCloser
+}
+
+func newCompressWriter(compression Compression, dst io.Writer) *compressWriter {
+	return &compressWriter{
+		compression: compression,
+		dst:         dst,
+	}
+}
+
+// Write implements io.Writer.
+func (w *compressWriter) Write(p []byte) (n int, err error) {
+	if !w.decided {
+		take := compressionSampleSize - len(w.sample)
+		if take > len(p) {
+			take = len(p)
+		}
+		w.sample = append(w.sample, p[:take]...)
+		if len(w.sample) < compressionSampleSize {
+			return len(p), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		n, p = take, p[take:]
+	}
+
+	var written int
+	if w.enc != nil {
+		written, err = w.enc.Write(p)
+	} else {
+		written, err = w.dst.Write(p)
+	}
+	return n + written, err
+}
+
+// decide compresses the sample to find out whether compression is worth it
+// and writes the sample to the destination.
+func (w *compressWriter) decide() (err error) {
+	w.decided = true
+	sample := w.sample
+	w.sample = nil
+
+	if len(sample) == 0 {
+		return nil
+	}
+
+	var compressed bytes.Buffer
+	enc, err := w.compression.newWriter(&compressed)
+	if err != nil {
+		return packageError.Wrap(err)
+	}
+	if _, err := enc.Write(sample); err != nil {
+		return packageError.Wrap(errs.Combine(err, enc.Close()))
+	}
+	if err := enc.Close(); err != nil {
+		return packageError.Wrap(err)
+	}
+
+	// skip compression when it saves less than 10%
+	if compressed.Len() >= len(sample)*9/10 {
+		_, err = w.dst.Write(sample)
+		return err
+	}
+
+	w.enc, err = w.compression.newWriter(w.dst)
+	if err != nil {
+		return packageError.Wrap(err)
+	}
+	_, err = w.enc.Write(sample)
+	return err
+}
+
+// Close flushes the remaining data. It returns whether the data was
+// compressed.
+func (w *compressWriter) Close() (compressed bool, err error) {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return false, err
+		}
+	}
+	if w.enc == nil {
+		return false, nil
+	}
+	return true, w.enc.Close()
+}
+
+// objectCompression returns the compression scheme recorded in the custom
+// metadata of an object. Unknown schemes are ignored, since the key may have
+// been set by another application.
+func objectCompression(custom map[string]string) Compression {
+	compression := Compression(custom[CompressionMetadataKey])
+	if compression.validate() != nil {
+		return CompressionNone
+	}
+	return compression
+}
+
+// checkRange returns an error when streamRange can't be downloaded from an
+// object compressed with the scheme. Ranges refer to the uncompressed data,
+// so they would need the whole object to be downloaded and decompressed.
+func (compression Compression) checkRange(streamRange metaclient.StreamRange) error {
+	if compression != CompressionNone && streamRange.Mode != metaclient.StreamRangeAll {
+		return packageError.New("ranged downloads are not supported for compressed objects")
+	}
+	return nil
+}
+
+// isCompressed returns whether the data read from r starts like data
+// compressed with the scheme.
+func (compression Compression) isCompressed(r *bufio.Reader) bool {
+	switch compression {
+	case CompressionGzip:
+		header, err := r.Peek(len(gzipHeader))
+		return err == nil && bytes.Equal(header, gzipHeader)
+	default:
+		return false
+	}
+}
+
+// gzipHeader are the first bytes of gzip data: the magic number and the
+// deflate compression method.
+var gzipHeader = []byte{0x1f, 0x8b, 0x08}
+
+// decompressReader decompresses object data.
+type decompressReader struct {
+	source io.Reader
+	dec    io.ReadCloser
+
+	compression Compression
+}
+
+// newDecompressReader returns a reader that decompresses source.
+func newDecompressReader(source io.Reader, compression Compression) *decompressReader {
+	return &decompressReader{
+		source:      source,
+		compression: compression,
+	}
+}
+
+// Read implements io.Reader. Data that isn't compressed with the scheme is
+// returned as is, since the scheme is only known from custom metadata, which
+// may have been set by another application.
+func (r *decompressReader) Read(p []byte) (n int, err error) {
+	if r.dec == nil {
+		buffered := bufio.NewReader(r.source)
+		if !r.compression.isCompressed(buffered) {
+			r.dec = io.NopCloser(buffered)
+			return r.dec.Read(p)
+		}
+		r.dec, err = r.compression.newReader(buffered)
+		if err != nil {
+			return 0, packageError.Wrap(err)
+		}
+	}
+
+	return r.dec.Read(p)
+}
+
+// Close releases the decompressor.
+func (r *decompressReader) Close() error {
+	if r.dec == nil {
+		return nil
+	}
+	return r.dec.Close()
+}
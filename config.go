@@ -9,6 +9,8 @@ import (
 	"time"
 	_ "unsafe" // for go:linkname
 
+	"go.opentelemetry.io/otel/trace"
+
 	"storj.io/common/rpc"
 	"storj.io/common/rpc/rpcpool"
 	"storj.io/common/useragent"
@@ -47,6 +49,70 @@ type Config struct {
 	// connections. This value is a hammer where we need a scalpel.
 	DialContext func(ctx context.Context, network, address string) (net.Conn, error)
 
+	// MetainfoTimeout defines how long a single request to the satellite may
+	// take. Requests are retried on some failures, and every attempt gets its
+	// own timeout. Per-call options, like ListObjectsOptions.Timeout, override
+	// it.
+	// Zero means no timeout.
+	MetainfoTimeout time.Duration
+
+	// TracerProvider enables OpenTelemetry tracing of uploads, downloads and
+	// metainfo requests, including per-segment and per-piece spans. The trace
+	// context is propagated to the satellite and storage nodes in the RPC
+	// metadata.
+	// If TracerProvider is nil, no OpenTelemetry spans are created.
+	TracerProvider trace.TracerProvider
+
+	// PlaintextCacheDir enables an on-disk read-through cache of downloaded
+	// objects. Objects that were downloaded completely are served from
+	// PlaintextCacheDir on later downloads, as long as the object on the
+	// satellite has not changed, which is checked on every download.
+	// The decrypted content is written to PlaintextCacheDir, so anyone who
+	// can read the directory can read the cached objects.
+	// If PlaintextCacheDir is empty, downloads are not cached.
+	PlaintextCacheDir string
+
+	// CacheMaxSize limits the total size of the objects in
+	// PlaintextCacheDir. The oldest objects are removed when the limit is
+	// exceeded.
+	// Zero means no limit.
+	CacheMaxSize int64
+
+	// CacheTTL defines how long an object is served from PlaintextCacheDir.
+	// Zero means no expiration.
+	CacheTTL time.Duration
+
+	// ContentCipher selects the cipher used to encrypt uploaded object
+	// content. The zero value uses AES-GCM. ContentCipherAuto picks the
+	// cipher based on hardware AES support.
+	ContentCipher ContentCipher
+
+	// LongTail configures when redundant piece transfers to slow storage
+	// nodes are cancelled. The zero value uses the defaults, which favor
+	// latency over bandwidth usage.
+	LongTail LongTailConfig
+
+	// Instrumentation receives the latency of public API calls, e.g. to
+	// publish them as histograms. Use MonkitInstrumentation to record them
+	// with monkit.
+	// If Instrumentation is nil, calls are not observed.
+	Instrumentation Instrumentation
+
+	// StatReuseWindow enables reusing the object information returned by
+	// StatObject for a DownloadObject of the same object that follows within
+	// the window, which saves a request to the satellite. The download may
+	// return an error or stale data when the object is replaced by another
+	// client within the window.
+	// Zero means the information is not reused.
+	StatReuseWindow time.Duration
+
+	// Scheduler limits the piece uploads and segments in progress across
+	// all projects opened with a Config that shares it. It only applies to
+	// uploads with concurrent segment uploads, which is the default, and not
+	// to downloads. See Scheduler for details.
+	// If Scheduler is nil, only the limits of every upload apply.
+	Scheduler *Scheduler
+
 	// satellitePool is a connection pool dedicated for satellite connections.
 	// If not set, the normal pool / default will be used.
 	satellitePool *rpcpool.Pool
@@ -75,6 +141,46 @@ type Config struct {
 	disableBackgroundQoS bool
 }
 
+// LongTailConfig defines how many piece transfers are made beyond the ones
+// that are needed, to avoid waiting for slow storage nodes.
+//
+// More transfers reduce the latency of uploads and downloads at the cost of
+// bandwidth.
+type LongTailConfig struct {
+	// SuccessThresholdMultiplier scales the number of successful piece
+	// uploads, relative to the success threshold of the redundancy scheme,
+	// after which the remaining piece uploads are cancelled. Values higher
+	// than 1 wait for more pieces to be stored, which uses more bandwidth.
+	// Zero means 1.
+	SuccessThresholdMultiplier float64
+
+	// MinimumSuccesses is the minimum number of successful piece uploads
+	// before the remaining piece uploads are cancelled. It can only increase
+	// the threshold given by the redundancy scheme.
+	MinimumSuccesses int
+
+	// DownloadExtraPieces is the number of pieces downloaded in parallel
+	// beyond the ones needed to reconstruct a segment. The storage nodes are
+	// chosen randomly, and the other storage nodes offered by the satellite
+	// are only used when a download from one of them fails. Lower values use
+	// less bandwidth, but make downloads more sensitive to slow storage nodes.
+	// Zero means all storage nodes offered by the satellite are used at once.
+	DownloadExtraPieces int
+}
+
+// validate checks whether the long-tail configuration is valid.
+func (config LongTailConfig) validate() error {
+	switch {
+	case config.SuccessThresholdMultiplier < 0:
+		return packageError.New("long tail success threshold multiplier must not be negative, got %v", config.SuccessThresholdMultiplier)
+	case config.MinimumSuccesses < 0:
+		return packageError.New("long tail minimum successes must not be negative, got %d", config.MinimumSuccesses)
+	case config.DownloadExtraPieces < 0:
+		return packageError.New("long tail download extra pieces must not be negative, got %d", config.DownloadExtraPieces)
+	}
+	return nil
+}
+
 // getDialer returns a new rpc.Dialer corresponding to the config.
 func (config Config) getDialer(ctx context.Context) (_ rpc.Dialer, err error) {
 	return config.getDialerForPool(ctx, nil)
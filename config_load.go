@@ -0,0 +1,157 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"storj.io/common/memory"
+)
+
+// ConfigEnvPrefix is the prefix of the environment variables read by
+// LoadConfig.
+const ConfigEnvPrefix = "UPLINK_"
+
+// configSettings are the settings that can be loaded with LoadConfig, by
+// name in the configuration file.
+var configSettings = map[string]func(config *Config, value string) error{
+	"user_agent": func(config *Config, value string) error {
+		config.UserAgent = value
+		return nil
+	},
+	"dial_timeout": func(config *Config, value string) (err error) {
+		config.DialTimeout, err = time.ParseDuration(value)
+		return err
+	},
+	"metainfo_timeout": func(config *Config, value string) (err error) {
+		config.MetainfoTimeout, err = time.ParseDuration(value)
+		return err
+	},
+	"plaintext_cache_dir": func(config *Config, value string) error {
+		config.PlaintextCacheDir = value
+		return nil
+	},
+	"cache_max_size": func(config *Config, value string) (err error) {
+		config.CacheMaxSize, err = memory.ParseString(value)
+		return err
+	},
+	"cache_ttl": func(config *Config, value string) (err error) {
+		config.CacheTTL, err = time.ParseDuration(value)
+		return err
+	},
+	"content_cipher": func(config *Config, value string) error {
+		switch value {
+		case "aes-gcm":
+			config.ContentCipher = ContentCipherAESGCM
+		case "secretbox":
+			config.ContentCipher = ContentCipherSecretBox
+		case "auto":
+			config.ContentCipher = ContentCipherAuto
+		default:
+			return fmt.Errorf("unknown content cipher %q, expected aes-gcm, secretbox or auto", value)
+		}
+		return nil
+	},
+	"long_tail_success_threshold_multiplier": func(config *Config, value string) (err error) {
+		config.LongTail.SuccessThresholdMultiplier, err = strconv.ParseFloat(value, 64)
+		return err
+	},
+	"long_tail_minimum_successes": func(config *Config, value string) (err error) {
+		config.LongTail.MinimumSuccesses, err = strconv.Atoi(value)
+		return err
+	},
+	"long_tail_download_extra_pieces": func(config *Config, value string) (err error) {
+		config.LongTail.DownloadExtraPieces, err = strconv.Atoi(value)
+		return err
+	},
+	"stat_reuse_window": func(config *Config, value string) (err error) {
+		config.StatReuseWindow, err = time.ParseDuration(value)
+		return err
+	},
+}
+
+// LoadConfig loads a Config, so it can be tuned without recompiling the
+// application.
+//
+// The file at path is a JSON object, e.g.
+//
+//	{"user_agent": "app/1.0", "metainfo_timeout": "30s", "cache_max_size": "1GiB"}
+//
+// The settings are user_agent, dial_timeout, metainfo_timeout,
+// plaintext_cache_dir, cache_max_size, cache_ttl, content_cipher (aes-gcm, secretbox or auto),
+// long_tail_success_threshold_multiplier, long_tail_minimum_successes,
+// long_tail_download_extra_pieces and stat_reuse_window. Durations use the
+// format of time.ParseDuration and sizes the format of memory.ParseString.
+//
+// Every setting can be overridden with an environment variable of the
+// upper-case name prefixed with ConfigEnvPrefix, e.g. UPLINK_USER_AGENT.
+// When path is empty, only the environment variables are used.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, packageError.Wrap(err)
+		}
+
+		var values map[string]interface{}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return Config{}, packageError.New("invalid config file %q: %v", path, err)
+		}
+
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			var value string
+			switch v := values[name].(type) {
+			case string:
+				value = v
+			case float64, bool:
+				value = fmt.Sprint(v)
+			default:
+				return Config{}, packageError.New("invalid config file %q: %s must be a string or a number", path, name)
+			}
+			if err := config.set(name, value); err != nil {
+				return Config{}, packageError.New("invalid config file %q: %v", path, err)
+			}
+		}
+	}
+
+	for name := range configSettings {
+		env := ConfigEnvPrefix + strings.ToUpper(name)
+		if value, ok := os.LookupEnv(env); ok {
+			if err := config.set(name, value); err != nil {
+				return Config{}, packageError.New("invalid %s: %v", env, err)
+			}
+		}
+	}
+
+	if err := config.LongTail.validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// set changes the setting name to value.
+func (config *Config) set(name, value string) error {
+	apply, ok := configSettings[name]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if err := apply(config, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
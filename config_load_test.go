@@ -0,0 +1,67 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/uplink"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "uplink.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"user_agent": "app/1.0",
+		"metainfo_timeout": "30s",
+		"cache_max_size": "1GiB",
+		"content_cipher": "secretbox",
+		"long_tail_minimum_successes": 10
+	}`), 0o600))
+
+	t.Setenv("UPLINK_USER_AGENT", "app/2.0")
+	t.Setenv("UPLINK_DIAL_TIMEOUT", "5s")
+
+	config, err := uplink.LoadConfig(path)
+	require.NoError(t, err)
+	require.Equal(t, "app/2.0", config.UserAgent)
+	require.Equal(t, 5*time.Second, config.DialTimeout)
+	require.Equal(t, 30*time.Second, config.MetainfoTimeout)
+	require.Equal(t, memory.GiB.Int64(), config.CacheMaxSize)
+	require.Equal(t, uplink.ContentCipherSecretBox, config.ContentCipher)
+	require.Equal(t, 10, config.LongTail.MinimumSuccesses)
+
+	config, err = uplink.LoadConfig("")
+	require.NoError(t, err)
+	require.Equal(t, "app/2.0", config.UserAgent)
+	require.Zero(t, config.MetainfoTimeout)
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	for _, contents := range []string{
+		`{"unknown": "x"}`,
+		`{"dial_timeout": "soon"}`,
+		`{"content_cipher": "rot13"}`,
+		`{"user_agent": ["a"]}`,
+		`not json`,
+	} {
+		path := filepath.Join(dir, "uplink.json")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+		_, err := uplink.LoadConfig(path)
+		require.Error(t, err, contents)
+	}
+
+	_, err := uplink.LoadConfig(filepath.Join(dir, "missing.json"))
+	require.Error(t, err)
+
+	t.Setenv("UPLINK_CACHE_TTL", "forever")
+	_, err = uplink.LoadConfig("")
+	require.Error(t, err)
+}
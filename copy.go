@@ -5,25 +5,35 @@ package uplink
 
 import (
 	"context"
+	"time"
 
 	"github.com/zeebo/errs"
+
+	"storj.io/uplink/private/metaclient"
 )
 
 // CopyObjectOptions options for CopyObject method.
 type CopyObjectOptions struct {
-	// may contain additional options in the future
+	// Timeout overrides Config.MetainfoTimeout for the requests of this call.
+	Timeout time.Duration
 }
 
 // CopyObject atomically copies object to a different bucket or/and key.
 func (project *Project) CopyObject(ctx context.Context, oldBucket, oldKey, newBucket, newKey string, options *CopyObjectOptions) (_ *Object, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if options != nil && options.Timeout != 0 {
+		ctx = metaclient.WithTimeout(ctx, options.Timeout)
+	}
+
 	db, err := dialMetainfoDB(ctx, project)
 	if err != nil {
 		return nil, packageError.Wrap(err)
 	}
 	defer func() { err = errs.Combine(err, db.Close()) }()
 
+	project.recentStats.forget(newBucket, newKey)
+
 	obj, err := db.CopyObject(ctx, oldBucket, oldKey, nil, newBucket, newKey)
 	if err != nil {
 		return nil, convertKnownErrors(err, oldBucket, oldKey)
@@ -0,0 +1,162 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/macaroon"
+	"storj.io/uplink/private/metaclient"
+)
+
+// defaultDeleteBatchSize is the default number of objects deleted in a single
+// request by DeletePrefix.
+const defaultDeleteBatchSize = 100
+
+// DeletePrefixOptions defines options for DeletePrefix.
+type DeletePrefixOptions struct {
+	// BatchSize is the number of objects deleted in a single request.
+	// Zero means 100.
+	BatchSize int
+
+	// Progress is called after every batch with the number of objects
+	// deleted and failed so far.
+	Progress func(deleted, failed int64)
+}
+
+// DeletePrefixResult describes the outcome of DeletePrefix.
+type DeletePrefixResult struct {
+	// Deleted is the number of deleted objects.
+	Deleted int64
+	// NotFound is the number of listed objects that didn't exist anymore
+	// when they were deleted, e.g. because they were deleted concurrently.
+	// The satellite only reports them when the access grant allows reading,
+	// otherwise they are counted as deleted.
+	NotFound int64
+	// Failed contains the objects that couldn't be deleted.
+	Failed []DeletePrefixFailure
+}
+
+// DeletePrefixFailure describes an object that couldn't be deleted.
+type DeletePrefixFailure struct {
+	Key string
+	Err error
+}
+
+// DeletePrefix deletes all objects under prefix in bucket. Objects are
+// deleted in batches. When a batch fails, its objects are deleted one by one,
+// so a single failing object doesn't stop the deletion of the others.
+//
+// The returned result is never nil and reports the objects that couldn't be
+// deleted. The error is non-nil when listing failed or some objects couldn't
+// be deleted.
+func (project *Project) DeletePrefix(ctx context.Context, bucket, prefix string, options *DeletePrefixOptions) (result *DeletePrefixResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result = &DeletePrefixResult{}
+
+	opts := DeletePrefixOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultDeleteBatchSize
+	}
+
+	db, err := project.dialMetainfoDB(ctx)
+	if err != nil {
+		return result, convertKnownErrors(err, bucket, "")
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	project.recentStats.forgetPrefix(bucket, prefix)
+
+	canRead := project.allowsReading(ctx, bucket)
+	deleteBatch := func(keys []string) {
+		project.deleteBatch(ctx, db, bucket, keys, canRead, result)
+		if opts.Progress != nil {
+			opts.Progress(result.Deleted, int64(len(result.Failed)))
+		}
+	}
+
+	keys := make([]string, 0, opts.BatchSize)
+	objects := project.ListObjects(ctx, bucket, &ListObjectsOptions{
+		Prefix:    prefix,
+		Recursive: true,
+	})
+	for objects.Next() {
+		keys = append(keys, objects.Item().Key)
+		if len(keys) >= opts.BatchSize {
+			deleteBatch(keys)
+			keys = keys[:0]
+		}
+	}
+	if err := objects.Err(); err != nil {
+		return result, err
+	}
+	if len(keys) > 0 {
+		deleteBatch(keys)
+	}
+
+	if len(result.Failed) > 0 {
+		return result, packageError.New("failed to delete %d objects, first error: %v", len(result.Failed), result.Failed[0].Err)
+	}
+	return result, nil
+}
+
+// deleteBatch deletes keys with a single request and falls back to deleting
+// them one by one when the request fails. When canRead is set, keys for which
+// the satellite didn't return the deleted object are counted as not found.
+func (project *Project) deleteBatch(ctx context.Context, db *metaclient.DB, bucket string, keys []string, canRead bool, result *DeletePrefixResult) {
+	count := func(found bool) {
+		if found || !canRead {
+			result.Deleted++
+		} else {
+			result.NotFound++
+		}
+	}
+
+	if found, err := db.DeleteObjects(ctx, bucket, keys); err == nil {
+		for _, ok := range found {
+			count(ok)
+		}
+		return
+	}
+
+	for _, key := range keys {
+		object, err := db.DeleteObject(ctx, bucket, key, nil)
+		if err != nil {
+			if metaclient.ErrObjectNotFound.Has(err) {
+				result.NotFound++
+				continue
+			}
+			result.Failed = append(result.Failed, DeletePrefixFailure{
+				Key: key,
+				Err: convertKnownErrors(err, bucket, key),
+			})
+			continue
+		}
+		count(object.Bucket.Name != "")
+	}
+}
+
+// allowsReading returns whether the access grant of the project allows
+// reading objects in bucket.
+func (project *Project) allowsReading(ctx context.Context, bucket string) bool {
+	allowed, err := project.access.apiKey.GetAllowedBuckets(ctx, macaroon.Action{
+		Op:   macaroon.ActionRead,
+		Time: time.Now(),
+	})
+	if err != nil {
+		return false
+	}
+	if allowed.All {
+		return true
+	}
+	_, ok := allowed.Buckets[bucket]
+	return ok
+}
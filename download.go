@@ -19,6 +19,7 @@ import (
 	"storj.io/common/leak"
 	"storj.io/common/paths"
 	"storj.io/eventkit"
+	"storj.io/uplink/private/diskcache"
 	"storj.io/uplink/private/metaclient"
 	"storj.io/uplink/private/storage/streams"
 	"storj.io/uplink/private/stream"
@@ -31,19 +32,45 @@ type DownloadOptions struct {
 	Offset int64
 	// When Length is negative it will read until the end of the blob.
 	Length int64
+
+	// Timeout overrides Config.MetainfoTimeout for the requests to the
+	// satellite of this download. Downloads from storage nodes are not
+	// affected.
+	Timeout time.Duration
 }
 
 // DownloadObject starts a download from the specific key.
 func (project *Project) DownloadObject(ctx context.Context, bucket, key string, options *DownloadOptions) (_ *Download, err error) {
-	return project.downloadObjectWithVersion(ctx, bucket, key, nil, options)
+	return project.downloadObject(ctx, bucket, key, nil, project.recentStats.take(bucket, key), options)
+}
+
+// DownloadObjectWithInfo starts a download of object, which was returned by
+// StatObject. It downloads the exact version described by object and, when
+// possible, reuses its information to avoid requesting it from the satellite
+// again.
+func (project *Project) DownloadObjectWithInfo(ctx context.Context, bucket string, object *Object, options *DownloadOptions) (_ *Download, err error) {
+	if object == nil {
+		return nil, packageError.New("object is nil")
+	}
+	return project.downloadObject(ctx, bucket, object.Key, object.version, object.stream, options)
 }
 
 func (project *Project) downloadObjectWithVersion(ctx context.Context, bucket, key string, version []byte, options *DownloadOptions) (_ *Download, err error) {
+	return project.downloadObject(ctx, bucket, key, version, nil, options)
+}
+
+// downloadObject starts a download. When stat is not nil, it's used instead
+// of requesting the object information from the satellite.
+func (project *Project) downloadObject(ctx context.Context, bucket, key string, version []byte, stat *metaclient.Object, options *DownloadOptions) (_ *Download, err error) {
 	download := &Download{
 		bucket: bucket,
 		stats:  newOperationStats(ctx, project.access.satelliteURL),
 	}
+	if options != nil && options.Timeout != 0 {
+		ctx = metaclient.WithTimeout(ctx, options.Timeout)
+	}
 	download.task = mon.TaskNamed("Download")(&ctx)
+	download.observe = project.instrument("DownloadObject")
 	defer func() {
 		if err != nil {
 			download.stats.flagFailure(err)
@@ -59,6 +86,9 @@ func (project *Project) downloadObjectWithVersion(ctx context.Context, bucket, k
 	if key == "" {
 		return nil, errwrapf("%w (%q)", ErrObjectKeyInvalid, key)
 	}
+	if err := project.inflight.err(); err != nil {
+		return nil, err
+	}
 
 	var opts metaclient.DownloadOptions
 	switch {
@@ -98,10 +128,32 @@ func (project *Project) downloadObjectWithVersion(ctx context.Context, bucket, k
 	}
 	defer func() { err = errs.Combine(err, db.Close()) }()
 
-	objectDownload, err := db.DownloadObject(ctx, bucket, key, version, opts)
+	objectDownload, ok, err := project.downloadInfoFromStat(bucket, key, stat, opts)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
 	}
+	if !ok {
+		objectDownload, err = db.DownloadObject(ctx, bucket, key, version, opts)
+		if err != nil {
+			return nil, convertKnownErrors(err, bucket, key)
+		}
+	}
+
+	var cacheKey string
+	if project.cache != nil {
+		cacheKey = project.cacheKey(bucket, key, &objectDownload.Object)
+		if cached := project.openCachedObject(cacheKey, objectDownload.Object); cached != nil {
+			if err := download.downloadFromCache(project, cached, opts); err != nil {
+				return nil, convertKnownErrors(err, bucket, key)
+			}
+			return download, nil
+		}
+	}
+
+	compression := objectCompression(objectDownload.Object.Metadata)
+	if err := compression.checkRange(opts.Range); err != nil {
+		return nil, err
+	}
 
 	download.stats.encPath = objectDownload.EncPath
 
@@ -117,6 +169,14 @@ func (project *Project) downloadObjectWithVersion(ctx context.Context, bucket, k
 		return nil, convertKnownErrors(err, bucket, key)
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	download.cancel = cancel
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
 	streams, err := project.getStreamsStore(ctx)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
@@ -125,10 +185,63 @@ func (project *Project) downloadObjectWithVersion(ctx context.Context, bucket, k
 
 	download.object = convertObject(&objectDownload.Object)
 	download.download = stream.NewDownloadRange(ctx, objectDownload, streams, streamRange.Start, streamRange.Limit-streamRange.Start)
+	download.data = download.download
+	if cacheKey != "" && streamRange.Start == 0 && streamRange.Limit == objectDownload.Object.Size {
+		download.data = project.cacheWhileReading(download, cacheKey)
+	}
+	if compression != CompressionNone {
+		download.decompress = newDecompressReader(download.data, compression)
+		download.data = download.decompress
+	}
 	download.tracker = project.tracker.Child("download", 1)
+
+	download.mu.Lock()
+	download.untrack, err = project.inflight.add(inflightOp{
+		cancel: cancel,
+		abort:  download.Close,
+	})
+	download.mu.Unlock()
+	if err != nil {
+		var group errs.Group
+		group.Add(err, download.download.Close(), streams.Close())
+		if download.decompress != nil {
+			group.Add(download.decompress.Close())
+		}
+		if download.cacheEntry != nil {
+			group.Add(download.cacheEntry.Abort())
+		}
+		group.Add(download.tracker.Close())
+		return nil, group.Err()
+	}
 	return download, nil
 }
 
+// downloadInfoFromStat builds the download information from the object
+// returned by StatObject. It returns false when the information isn't
+// sufficient, e.g. for multipart objects, whose segment sizes aren't known.
+func (project *Project) downloadInfoFromStat(bucket, key string, stat *metaclient.Object, opts metaclient.DownloadOptions) (_ metaclient.DownloadInfo, ok bool, err error) {
+	if stat == nil || len(stat.Stream.ID) == 0 || stat.Stream.FixedSegmentSize <= 0 {
+		return metaclient.DownloadInfo{}, false, nil
+	}
+
+	encPath, err := encryptPath(project, bucket, key)
+	if err != nil {
+		return metaclient.DownloadInfo{}, false, err
+	}
+
+	segments := make([]metaclient.SegmentListItem, stat.Stream.SegmentCount)
+	for i := range segments {
+		segments[i].Position = metaclient.SegmentPosition{Index: int32(i)}
+	}
+
+	return metaclient.DownloadInfo{
+		Object:       *stat,
+		EncPath:      encPath,
+		ListSegments: metaclient.ListSegmentsResponse{Items: segments},
+		Range:        opts.Range.Normalize(stat.Size),
+	}, true, nil
+}
+
 // Download is a download from Storj Network.
 type Download struct {
 	mu       sync.Mutex
@@ -137,12 +250,27 @@ type Download struct {
 	bucket   string
 	streams  *streams.Store
 
+	data       io.Reader
+	decompress *decompressReader
+	cacheFile  io.Closer
+	cacheEntry *diskcache.Entry
+
 	sizes struct {
 		offset, length, total int64
 	}
-	ttfb  time.Duration
-	stats operationStats
-	task  func(*error)
+	ttfb    time.Duration
+	stats   operationStats
+	task    func(*error)
+	observe func(*error)
+
+	// readMu serializes Read and Close, so Project.Shutdown can close the
+	// download while it's being read.
+	readMu  sync.Mutex
+	cancel  context.CancelFunc
+	untrack func()
+
+	closeOnce sync.Once
+	closeErr  error
 
 	tracker leak.Ref
 }
@@ -156,7 +284,9 @@ func (download *Download) Info() *Object {
 // It returns the number of bytes read (0 <= n <= len(p)) and any error encountered.
 func (download *Download) Read(p []byte) (n int, err error) {
 	track := download.stats.trackWorking()
-	n, err = download.download.Read(p)
+	download.readMu.Lock()
+	n, err = download.data.Read(p)
+	download.readMu.Unlock()
 	download.mu.Lock()
 	download.stats.bytes += int64(n)
 	if err != nil && !errors.Is(err, io.EOF) {
@@ -170,14 +300,48 @@ func (download *Download) Read(p []byte) (n int, err error) {
 	return n, convertKnownErrors(err, download.bucket, download.object.Key)
 }
 
-// Close closes the reader of the download.
+// Close closes the reader of the download. Calling it more than once returns
+// the result of the first call.
 func (download *Download) Close() error {
+	download.closeOnce.Do(func() {
+		download.closeErr = download.close()
+	})
+	return download.closeErr
+}
+
+func (download *Download) close() error {
 	track := download.stats.trackWorking()
-	err := errs.Combine(
-		download.download.Close(),
-		download.streams.Close(),
-		download.tracker.Close(),
-	)
+	if download.cancel != nil {
+		download.cancel()
+	}
+	// the download is only untracked once it's closed, so Shutdown waits
+	// for the close in progress.
+	download.mu.Lock()
+	untrack := download.untrack
+	download.mu.Unlock()
+	if untrack != nil {
+		defer untrack()
+	}
+
+	download.readMu.Lock()
+	defer download.readMu.Unlock()
+
+	var group errs.Group
+	if download.download != nil {
+		group.Add(download.download.Close(), download.streams.Close())
+	}
+	if download.decompress != nil {
+		group.Add(download.decompress.Close())
+	}
+	if download.cacheFile != nil {
+		group.Add(download.cacheFile.Close())
+	}
+	if download.cacheEntry != nil {
+		// the entry is only committed once the whole object was read.
+		group.Add(download.cacheEntry.Abort())
+	}
+	group.Add(download.tracker.Close())
+	err := group.Err()
 	download.mu.Lock()
 	track()
 	download.stats.flagFailure(err)
@@ -198,6 +362,9 @@ func pathChecksum(encPath paths.Encrypted) []byte {
 func (download *Download) emitEvent() {
 	message, err := download.stats.err()
 	download.task(&err)
+	if download.observe != nil {
+		download.observe(&err)
+	}
 
 	evs.Event("download",
 		eventkit.Int64("bytes", download.stats.bytes),
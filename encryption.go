@@ -5,6 +5,7 @@ package uplink
 
 import (
 	"storj.io/common/encryption"
+	"storj.io/common/paths"
 	"storj.io/common/storj"
 )
 
@@ -26,3 +27,32 @@ func DeriveEncryptionKey(passphrase string, salt []byte) (*EncryptionKey, error)
 	}
 	return &EncryptionKey{key: key}, nil
 }
+
+// Bytes returns a copy of the raw key.
+func (key *EncryptionKey) Bytes() []byte {
+	raw := *key.key
+	return raw[:]
+}
+
+// DeriveObjectKey derives the key that encrypts the content of the object
+// at key in bucket.
+//
+// The content of every segment is encrypted with a random key, which is
+// stored encrypted with the derived key in the segment metadata. So the
+// derived key allows decrypting the content of a single object, e.g. by
+// out-of-band decryption tools, without revealing the keys of other
+// objects or the root passphrase.
+func (access *Access) DeriveObjectKey(bucket, key string) (*EncryptionKey, error) {
+	if bucket == "" {
+		return nil, errwrapf("%w (%q)", ErrBucketNameInvalid, bucket)
+	}
+	if key == "" {
+		return nil, errwrapf("%w (%q)", ErrObjectKeyInvalid, key)
+	}
+
+	derived, err := encryption.DeriveContentKey(bucket, paths.NewUnencrypted(key), access.encAccess.Store)
+	if err != nil {
+		return nil, convertKnownErrors(err, bucket, key)
+	}
+	return &EncryptionKey{key: derived}, nil
+}
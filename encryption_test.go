@@ -0,0 +1,64 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/encryption"
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+	"storj.io/common/paths"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+)
+
+func TestDeriveObjectKey(t *testing.T) {
+	apiKey, err := macaroon.NewAPIKey(testrand.Bytes(32))
+	require.NoError(t, err)
+
+	rootKey := testrand.Key()
+	encAccess := grant.NewEncryptionAccessWithDefaultKey(&rootKey)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+
+	serialized, err := (&grant.Access{
+		SatelliteAddress: "12EayRS2V1kEsWESU9QMRseFhdxYxKicsiFmxrsLZHeLUtdps3S@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}).Serialize()
+	require.NoError(t, err)
+
+	access, err := uplink.ParseAccess(serialized)
+	require.NoError(t, err)
+
+	objectKey, err := access.DeriveObjectKey("bucket", "a/b/c")
+	require.NoError(t, err)
+
+	expected, err := encryption.DeriveContentKey("bucket", paths.NewUnencrypted("a/b/c"), encAccess.Store)
+	require.NoError(t, err)
+	require.Equal(t, expected[:], objectKey.Bytes())
+
+	other, err := access.DeriveObjectKey("bucket", "a/b/d")
+	require.NoError(t, err)
+	require.NotEqual(t, objectKey.Bytes(), other.Bytes())
+
+	_, err = access.DeriveObjectKey("", "a/b/c")
+	require.ErrorIs(t, err, uplink.ErrBucketNameInvalid)
+	_, err = access.DeriveObjectKey("bucket", "")
+	require.ErrorIs(t, err, uplink.ErrObjectKeyInvalid)
+
+	// a shared access can only derive keys within the shared prefix
+	shared, err := access.Share(uplink.ReadOnlyPermission(), uplink.SharePrefix{Bucket: "bucket", Prefix: "a/b/"})
+	require.NoError(t, err)
+
+	sharedKey, err := shared.DeriveObjectKey("bucket", "a/b/c")
+	require.NoError(t, err)
+	require.Equal(t, objectKey.Bytes(), sharedKey.Bytes())
+
+	_, err = shared.DeriveObjectKey("bucket", "x/y")
+	require.ErrorIs(t, err, uplink.ErrPermissionDenied)
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+)
+
+// Call describes a finished public API call.
+type Call struct {
+	// Name is the name of the called method, e.g. "UploadObject".
+	Name string
+	// Satellite is the node URL of the satellite of the project.
+	Satellite string
+	// Duration is how long the call took. Uploads and downloads finish
+	// with Commit, Abort or Close of the returned Upload or Download, so
+	// their duration includes the transfer of the data.
+	Duration time.Duration
+	// Err is the error of the call. For uploads and downloads it's any error
+	// of the operation up to its end. Aborted uploads, which didn't fail
+	// otherwise, report context.Canceled.
+	Err error
+}
+
+// Instrumentation receives the latency of public API calls, so services
+// built on uplink can publish it to their metrics registry.
+//
+// Currently UploadObject, DownloadObject, StatObject and the requests of
+// ListObjects are observed. Uploads and downloads are observed when they
+// finish. Every page of ListObjects is a separate call.
+type Instrumentation interface {
+	// ObserveCall is called when a call finishes. It must not block.
+	ObserveCall(call Call)
+}
+
+// MonkitInstrumentation returns an Instrumentation that records the call
+// durations as "uplink_call_duration" in scope, tagged by call, satellite and
+// success.
+func MonkitInstrumentation(scope *monkit.Scope) Instrumentation {
+	return monkitInstrumentation{scope: scope}
+}
+
+type monkitInstrumentation struct {
+	scope *monkit.Scope
+}
+
+// ObserveCall implements Instrumentation.
+func (instrumentation monkitInstrumentation) ObserveCall(call Call) {
+	instrumentation.scope.DurationVal("uplink_call_duration",
+		monkit.NewSeriesTag("call", call.Name),
+		monkit.NewSeriesTag("satellite", call.Satellite),
+		monkit.NewSeriesTag("success", boolTag(call.Err == nil)),
+	).Observe(call.Duration)
+}
+
+func boolTag(v bool) string {
+	if v {
+		return "true"
+	}
+	return "false"
+}
+
+// instrument starts observing the call name. The returned function must be
+// called with the error of the call when it finishes.
+func (project *Project) instrument(name string) func(*error) {
+	instrumentation := project.config.Instrumentation
+	if instrumentation == nil {
+		return func(*error) {}
+	}
+
+	start := time.Now()
+	return func(errp *error) {
+		var err error
+		if errp != nil {
+			err = *errp
+		}
+		instrumentation.ObserveCall(Call{
+			Name:      name,
+			Satellite: project.access.satelliteURL.String(),
+			Duration:  time.Since(start),
+			Err:       err,
+		})
+	}
+}
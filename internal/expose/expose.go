@@ -5,6 +5,8 @@ package expose
 
 import (
 	"context"
+	"io"
+	"time"
 	_ "unsafe" // for go:linkname
 
 	"storj.io/common/grant"
@@ -60,3 +62,44 @@ func ConfigRequestAccessWithPassphraseAndConcurrency(config uplink.Config, ctx c
 //
 //go:linkname ConfigDisableBackgroundQoS storj.io/uplink.config_disableBackgroundQoS
 func ConfigDisableBackgroundQoS(config *uplink.Config, disabled bool)
+
+// ReuploadObject exposes reuploadObject.
+//
+//go:linkname ReuploadObject storj.io/uplink.reuploadObject
+//nolint:revive
+func ReuploadObject(ctx context.Context, source uplink.ProjectAPI, sourceBucket string, object *uplink.Object, target uplink.ProjectAPI, bucket, key string, tee io.Writer) (*uplink.Object, error)
+
+// UploadNewLocal exposes upload_newLocal.
+//
+//go:linkname UploadNewLocal storj.io/uplink.upload_newLocal
+func UploadNewLocal(ctx context.Context, bucket string, object *uplink.Object, commit func(data []byte, object *uplink.Object) (time.Time, error)) *uplink.Upload
+
+// PartUploadNewLocal exposes partUpload_newLocal.
+//
+//go:linkname PartUploadNewLocal storj.io/uplink.partUpload_newLocal
+func PartUploadNewLocal(ctx context.Context, bucket, key string, part *uplink.Part, commit func(data []byte, part *uplink.Part) (time.Time, error)) *uplink.PartUpload
+
+// DownloadNewLocal exposes download_newLocal.
+//
+//go:linkname DownloadNewLocal storj.io/uplink.download_newLocal
+func DownloadNewLocal(ctx context.Context, bucket string, object *uplink.Object, data []byte) *uplink.Download
+
+// ObjectIteratorNewLocal exposes objectIterator_newLocal.
+//
+//go:linkname ObjectIteratorNewLocal storj.io/uplink.objectIterator_newLocal
+func ObjectIteratorNewLocal(objects []*uplink.Object, options *uplink.ListObjectsOptions, err error) *uplink.ObjectIterator
+
+// BucketIteratorNewLocal exposes bucketIterator_newLocal.
+//
+//go:linkname BucketIteratorNewLocal storj.io/uplink.bucketIterator_newLocal
+func BucketIteratorNewLocal(buckets []*uplink.Bucket, err error) *uplink.BucketIterator
+
+// UploadIteratorNewLocal exposes uploadIterator_newLocal.
+//
+//go:linkname UploadIteratorNewLocal storj.io/uplink.uploadIterator_newLocal
+func UploadIteratorNewLocal(uploads []*uplink.UploadInfo, options *uplink.ListUploadsOptions, err error) *uplink.UploadIterator
+
+// PartIteratorNewLocal exposes partIterator_newLocal.
+//
+//go:linkname PartIteratorNewLocal storj.io/uplink.partIterator_newLocal
+func PartIteratorNewLocal(parts []*uplink.Part, err error) *uplink.PartIterator
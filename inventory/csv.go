@@ -0,0 +1,72 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inventory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVHeader is the header row written by CSVWriter.
+var CSVHeader = []string{"key", "size", "created", "expires", "custom"}
+
+// CSVWriter writes records as CSV rows. Times are formatted as RFC 3339 and
+// are empty when not set. Custom metadata is encoded as a JSON object.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter returns a writer of CSV rows to w. When header is set,
+// CSVHeader is written before the first record; it should not be set when
+// continuing an export into the same file.
+func NewCSVWriter(w io.Writer, header bool) *CSVWriter {
+	return &CSVWriter{
+		w:      csv.NewWriter(w),
+		header: header,
+	}
+}
+
+// Write implements Writer.
+func (w *CSVWriter) Write(record Record) error {
+	if w.header {
+		if err := w.w.Write(CSVHeader); err != nil {
+			return Error.Wrap(err)
+		}
+		w.header = false
+	}
+
+	custom := ""
+	if len(record.Custom) > 0 {
+		data, err := json.Marshal(record.Custom)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		custom = string(data)
+	}
+
+	return Error.Wrap(w.w.Write([]string{
+		record.Key,
+		strconv.FormatInt(record.Size, 10),
+		formatTime(record.Created),
+		formatTime(record.Expires),
+		custom,
+	}))
+}
+
+// Flush implements Writer.
+func (w *CSVWriter) Flush() error {
+	w.w.Flush()
+	return Error.Wrap(w.w.Error())
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package inventory_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink"
+	"storj.io/uplink/inventory"
+)
+
+func TestCSVWriter(t *testing.T) {
+	created := time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	var buf bytes.Buffer
+	w := inventory.NewCSVWriter(&buf, true)
+	require.NoError(t, w.Write(inventory.Record{
+		Key:     "a/b,c",
+		Size:    10,
+		Created: created,
+		Custom:  uplink.CustomMetadata{"k": "v"},
+	}))
+	require.NoError(t, w.Write(inventory.Record{
+		Key:     "d",
+		Created: created,
+		Expires: created.Add(time.Hour),
+	}))
+	require.NoError(t, w.Flush())
+
+	require.Equal(t, ""+
+		"key,size,created,expires,custom\n"+
+		"\"a/b,c\",10,2024-02-03T04:05:06Z,,\"{\"\"k\"\":\"\"v\"\"}\"\n"+
+		"d,0,2024-02-03T04:05:06Z,2024-02-03T05:05:06Z,\n",
+		buf.String())
+
+	// without header nothing is written before the records
+	buf.Reset()
+	w = inventory.NewCSVWriter(&buf, false)
+	require.NoError(t, w.Write(inventory.Record{Key: "e"}))
+	require.NoError(t, w.Flush())
+	require.Equal(t, "e,0,,,\n", buf.String())
+}
@@ -0,0 +1,129 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package inventory exports bucket listings, e.g. for reconciliation against
+// an external database.
+package inventory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for this package.
+var Error = errs.Class("inventory")
+
+// Record describes a single object in the inventory.
+type Record struct {
+	Key     string
+	Size    int64
+	Created time.Time
+	Expires time.Time
+	Custom  uplink.CustomMetadata
+}
+
+// Writer writes inventory records in some format.
+type Writer interface {
+	// Write writes a single record.
+	Write(record Record) error
+	// Flush writes any buffered records.
+	Flush() error
+}
+
+// Options configures an export.
+type Options struct {
+	// Prefix limits the export to objects with the prefix.
+	// If not empty, it must end with slash.
+	Prefix string
+	// Cursor continues an export after the cursor. It is relative to Prefix.
+	Cursor string
+	// Custom includes the custom metadata in the records.
+	Custom bool
+}
+
+// flushInterval is the number of records after which the writer is flushed
+// and the cursor advanced.
+const flushInterval = 1000
+
+// Export writes a record of every object in the bucket to w, listing
+// recursively.
+//
+// It returns the cursor of the last record that was written and flushed,
+// even when the export fails, so a failed export can be continued by
+// setting Options.Cursor to it. Records after the cursor may have reached
+// the underlying writer of w before the failure, so they can appear twice
+// in a continued export.
+func Export(ctx context.Context, project *uplink.Project, bucket string, w Writer, options *Options) (cursor string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if options == nil {
+		options = &Options{}
+	}
+
+	cursor = options.Cursor
+	unflushed := cursor
+	pending := 0
+
+	defer func() {
+		// on failure, try to save what was written so far.
+		if err != nil {
+			if flushErr := flush(w, &cursor, unflushed); flushErr != nil {
+				err = errs.Combine(err, Error.Wrap(flushErr))
+			}
+		}
+	}()
+
+	objects := project.ListObjects(ctx, bucket, &uplink.ListObjectsOptions{
+		Prefix:    options.Prefix,
+		Cursor:    options.Cursor,
+		Recursive: true,
+		System:    true,
+		Custom:    options.Custom,
+	})
+	for objects.Next() {
+		item := objects.Item()
+		record := Record{
+			Key:     item.Key,
+			Size:    item.System.ContentLength,
+			Created: item.System.Created,
+			Expires: item.System.Expires,
+		}
+		if options.Custom {
+			record.Custom = item.Custom
+		}
+		if err := w.Write(record); err != nil {
+			return cursor, Error.Wrap(err)
+		}
+		unflushed = strings.TrimPrefix(item.Key, options.Prefix)
+
+		pending++
+		if pending >= flushInterval {
+			if err := flush(w, &cursor, unflushed); err != nil {
+				return cursor, Error.Wrap(err)
+			}
+			pending = 0
+		}
+	}
+	if err := objects.Err(); err != nil {
+		return cursor, Error.Wrap(err)
+	}
+
+	return cursor, Error.Wrap(flush(w, &cursor, unflushed))
+}
+
+// flush flushes w and advances cursor when it succeeds.
+func flush(w Writer, cursor *string, unflushed string) error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	*cursor = unflushed
+	return nil
+}
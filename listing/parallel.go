@@ -0,0 +1,182 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package listing contains helpers for listing large buckets.
+package listing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for this package.
+var Error = errs.Class("listing")
+
+// Iterator is an iterator over the objects listed by Parallel.
+type Iterator struct {
+	cancel context.CancelFunc
+	items  chan *uplink.Object
+	err    error
+	item   *uplink.Object
+}
+
+// Parallel lists all objects under prefixes recursively, listing up to
+// workers prefixes at the same time, and merges the results into a single
+// iterator. The objects include system metadata.
+//
+// The prefixes are split further: every prefix is listed non-recursively
+// and the prefixes found in it are listed the same way, so a bucket is
+// spread over the workers by its directory structure. Object keys are
+// encrypted, so listings can't be split by key ranges, and the objects
+// directly under a single prefix are listed by a single worker.
+//
+// Objects are returned in no particular order. Objects under overlapping
+// prefixes are returned more than once. When prefixes is empty, the whole
+// bucket is listed. Prefixes must be empty or end with a slash.
+//
+// The iterator must be closed when it is not read until the end.
+func Parallel(ctx context.Context, project uplink.ProjectAPI, bucket string, prefixes []string, workers int) *Iterator {
+	defer mon.Task()(&ctx)(nil)
+
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	iterator := &Iterator{
+		cancel: cancel,
+		items:  make(chan *uplink.Object, workers),
+	}
+
+	go func() {
+		defer cancel()
+		defer close(iterator.items)
+
+		// iterator.err is only read after items is closed.
+		iterator.err = Error.Wrap(iterator.walk(ctx, project, bucket, prefixes, workers))
+	}()
+
+	return iterator
+}
+
+// listed is the result of listing a prefix.
+type listed struct {
+	prefixes []string
+	err      error
+}
+
+// walk lists prefixes and the prefixes found in them with workers
+// goroutines, until everything was listed or a listing failed.
+func (iterator *Iterator) walk(ctx context.Context, project uplink.ProjectAPI, bucket string, prefixes []string, workers int) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pending := make(chan string)
+	done := make(chan listed)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer close(pending)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range pending {
+				found, err := iterator.list(ctx, project, bucket, prefix)
+				done <- listed{prefixes: found, err: err}
+			}
+		}()
+	}
+
+	queue := append([]string{}, prefixes...)
+	active := 0
+	for len(queue) > 0 || active > 0 {
+		// only hand out a prefix when there is one.
+		var next chan string
+		var prefix string
+		if len(queue) > 0 && err == nil {
+			next, prefix = pending, queue[len(queue)-1]
+		}
+
+		select {
+		case next <- prefix:
+			queue = queue[:len(queue)-1]
+			active++
+		case result := <-done:
+			active--
+			if result.err != nil && err == nil {
+				// stop the other listings and wait for them to finish.
+				err = result.err
+				cancel()
+			}
+			queue = append(queue, result.prefixes...)
+		}
+		if err != nil && active == 0 {
+			break
+		}
+	}
+	return err
+}
+
+// list sends the objects directly under prefix to the iterator and returns
+// the prefixes under it.
+func (iterator *Iterator) list(ctx context.Context, project uplink.ProjectAPI, bucket, prefix string) (prefixes []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	objects := project.ListObjects(ctx, bucket, &uplink.ListObjectsOptions{
+		Prefix: prefix,
+		System: true,
+	})
+	for objects.Next() {
+		item := objects.Item()
+		if item.IsPrefix {
+			prefixes = append(prefixes, item.Key)
+			continue
+		}
+		select {
+		case iterator.items <- item:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return prefixes, objects.Err()
+}
+
+// Next prepares the next object for reading. It returns false when all
+// prefixes have been listed or when a listing failed.
+func (iterator *Iterator) Next() bool {
+	item, ok := <-iterator.items
+	iterator.item = item
+	return ok
+}
+
+// Item returns the current object.
+func (iterator *Iterator) Item() *uplink.Object {
+	return iterator.item
+}
+
+// Err returns the error of the first failed listing. It must only be called
+// after Next returned false.
+func (iterator *Iterator) Err() error {
+	return iterator.err
+}
+
+// Close stops the listings that are still running.
+func (iterator *Iterator) Close() {
+	iterator.cancel()
+	for range iterator.items {
+	}
+}
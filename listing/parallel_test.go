@@ -0,0 +1,102 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package listing_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+	"storj.io/uplink/listing"
+	"storj.io/uplink/uplinktest"
+)
+
+func TestParallel(t *testing.T) {
+	ctx := context.Background()
+
+	inMemory := uplinktest.NewProject()
+	project := &recordingProject{ProjectAPI: inMemory}
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	keys := []string{
+		"a/1", "a/2", "a/b/1", "a/b/c/1",
+		"d/1", "d/e/1",
+		"top",
+	}
+	for _, key := range keys {
+		upload, err := project.UploadObject(ctx, "bucket", key, nil)
+		require.NoError(t, err)
+		_, err = upload.Write(testrand.Bytes(10))
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+	}
+
+	collect := func(iterator *listing.Iterator) (keys []string) {
+		for iterator.Next() {
+			require.False(t, iterator.Item().IsPrefix)
+			require.EqualValues(t, 10, iterator.Item().System.ContentLength)
+			keys = append(keys, iterator.Item().Key)
+		}
+		require.NoError(t, iterator.Err())
+		return keys
+	}
+
+	// the bucket is split into the prefixes found in it.
+	require.ElementsMatch(t, keys, collect(listing.Parallel(ctx, project, "bucket", nil, 3)))
+	require.ElementsMatch(t, []string{"", "a/", "a/b/", "a/b/c/", "d/", "d/e/"}, project.listed())
+
+	project.reset()
+	require.ElementsMatch(t, []string{"a/b/1", "a/b/c/1", "d/1", "d/e/1"}, collect(listing.Parallel(ctx, project, "bucket", []string{"a/b/", "d/"}, 2)))
+	require.ElementsMatch(t, []string{"a/b/", "a/b/c/", "d/", "d/e/"}, project.listed())
+
+	// closing early stops the listings.
+	iterator := listing.Parallel(ctx, project, "bucket", nil, 3)
+	require.True(t, iterator.Next())
+	iterator.Close()
+	require.False(t, iterator.Next())
+
+	injected := errors.New("injected")
+	inMemory.FailNext("ListObjects", injected)
+	iterator = listing.Parallel(ctx, project, "bucket", nil, 3)
+	for iterator.Next() {
+	}
+	require.ErrorIs(t, iterator.Err(), injected)
+
+	iterator = listing.Parallel(ctx, project, "missing", nil, 3)
+	require.False(t, iterator.Next())
+	require.ErrorIs(t, iterator.Err(), uplink.ErrBucketNotFound)
+}
+
+// recordingProject records the prefixes of the listings.
+type recordingProject struct {
+	uplink.ProjectAPI
+
+	mu       sync.Mutex
+	prefixes []string
+}
+
+func (project *recordingProject) ListObjects(ctx context.Context, bucket string, options *uplink.ListObjectsOptions) *uplink.ObjectIterator {
+	project.mu.Lock()
+	project.prefixes = append(project.prefixes, options.Prefix)
+	project.mu.Unlock()
+	return project.ProjectAPI.ListObjects(ctx, bucket, options)
+}
+
+func (project *recordingProject) listed() []string {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+	return append([]string{}, project.prefixes...)
+}
+
+func (project *recordingProject) reset() {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+	project.prefixes = nil
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"bytes"
+	"context"
+	"time"
+	_ "unsafe" // for go:linkname
+
+	"storj.io/common/base58"
+	"storj.io/common/storj"
+	"storj.io/uplink/private/metaclient"
+	"storj.io/uplink/private/storage/streams"
+)
+
+// The functions in this file create uploads, downloads and iterators that
+// aren't connected to a satellite. They are used by uplinktest to implement
+// ProjectAPI in memory.
+
+// localUpload keeps the uploaded data in memory and hands it to commit.
+type localUpload struct {
+	data   bytes.Buffer
+	commit func(data []byte) (modified time.Time, err error)
+	meta   *streams.Meta
+}
+
+func (upload *localUpload) Write(p []byte) (int, error) {
+	if upload.meta != nil {
+		return 0, errwrapf("%w: already committed", ErrUploadDone)
+	}
+	return upload.data.Write(p)
+}
+
+func (upload *localUpload) Commit() error {
+	modified, err := upload.commit(upload.data.Bytes())
+	if err != nil {
+		return err
+	}
+	upload.meta = &streams.Meta{
+		Modified: modified,
+		Size:     int64(upload.data.Len()),
+	}
+	return nil
+}
+
+func (upload *localUpload) Abort() error {
+	upload.data.Reset()
+	return nil
+}
+
+func (upload *localUpload) Meta() *streams.Meta { return upload.meta }
+
+// upload_newLocal returns an upload of object, which passes the data and
+// the final object information to commit.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname upload_newLocal
+func upload_newLocal(ctx context.Context, bucket string, object *Object, commit func(data []byte, object *Object) (time.Time, error)) *Upload {
+	upload := &Upload{
+		bucket: bucket,
+		object: object,
+		cancel: func() {},
+		stats:  newOperationStats(ctx, storj.NodeURL{}),
+		task:   func(*error) {},
+	}
+	upload.upload = &localUpload{
+		commit: func(data []byte) (time.Time, error) {
+			return commit(data, upload.object)
+		},
+	}
+	return upload
+}
+
+// partUpload_newLocal returns an upload of part, which passes the data and
+// the final part information to commit.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname partUpload_newLocal
+func partUpload_newLocal(ctx context.Context, bucket, key string, part *Part, commit func(data []byte, part *Part) (time.Time, error)) *PartUpload {
+	upload := &PartUpload{
+		bucket: bucket,
+		key:    key,
+		part:   part,
+		cancel: func() {},
+		eTagCh: make(chan []byte, 1),
+		stats:  newOperationStats(ctx, storj.NodeURL{}),
+		task:   func(*error) {},
+	}
+	upload.upload = &localUpload{
+		commit: func(data []byte) (time.Time, error) {
+			return commit(data, upload.part)
+		},
+	}
+	return upload
+}
+
+// download_newLocal returns a download of object, which reads data.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname download_newLocal
+func download_newLocal(ctx context.Context, bucket string, object *Object, data []byte) *Download {
+	download := &Download{
+		bucket: bucket,
+		object: object,
+		data:   bytes.NewReader(data),
+		stats:  newOperationStats(ctx, storj.NodeURL{}),
+		task:   func(*error) {},
+	}
+	download.sizes.length = int64(len(data))
+	download.sizes.total = object.System.ContentLength
+	return download
+}
+
+// objectIterator_newLocal returns an iterator over objects. When err is not
+// nil, the iteration fails with it.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname objectIterator_newLocal
+func objectIterator_newLocal(objects []*Object, options *ListObjectsOptions, err error) *ObjectIterator {
+	iterator := &ObjectIterator{
+		list:     &metaclient.ObjectList{},
+		position: -1,
+		err:      err,
+	}
+	if options != nil {
+		iterator.objOptions = *options
+	}
+	for _, object := range objects {
+		item := metaclient.Object{
+			Path:     object.Key,
+			IsPrefix: object.IsPrefix,
+			Created:  object.System.Created,
+			Expires:  object.System.Expires,
+			Metadata: object.Custom,
+		}
+		item.Size = object.System.ContentLength
+		iterator.list.Items = append(iterator.list.Items, item)
+	}
+	if iterator.objOptions.SortBy != SortByNone {
+		sortObjects(iterator.list.Items, iterator.objOptions.SortBy, iterator.objOptions.SortDescending)
+	}
+	return iterator
+}
+
+// bucketIterator_newLocal returns an iterator over buckets. When err is not
+// nil, the iteration fails with it.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname bucketIterator_newLocal
+func bucketIterator_newLocal(buckets []*Bucket, err error) *BucketIterator {
+	items := make([]metaclient.Bucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		items = append(items, metaclient.Bucket{
+			Name:    bucket.Name,
+			Created: bucket.Created,
+		})
+	}
+	return &BucketIterator{
+		iterator: metaclient.NewStaticBucketIterator(items, err),
+	}
+}
+
+// uploadIterator_newLocal returns an iterator over uploads. When err is not
+// nil, the iteration fails with it.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname uploadIterator_newLocal
+func uploadIterator_newLocal(uploads []*UploadInfo, options *ListUploadsOptions, err error) *UploadIterator {
+	iterator := &UploadIterator{
+		list:     &metaclient.ObjectList{},
+		position: -1,
+		err:      err,
+	}
+	if options != nil {
+		iterator.uploadOptions = *options
+	}
+	for _, upload := range uploads {
+		streamID, _, _ := base58.CheckDecode(upload.UploadID)
+		item := metaclient.Object{
+			Path:     upload.Key,
+			IsPrefix: upload.IsPrefix,
+			Created:  upload.System.Created,
+			Expires:  upload.System.Expires,
+			Metadata: upload.Custom,
+		}
+		item.Stream.ID = streamID
+		item.Size = upload.System.ContentLength
+		iterator.list.Items = append(iterator.list.Items, item)
+	}
+	return iterator
+}
+
+// partIterator_newLocal returns an iterator over parts. When err is not nil,
+// the iteration fails with it.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//lint:ignore U1000, used with linkname
+//nolint:unused
+//go:linkname partIterator_newLocal
+func partIterator_newLocal(parts []*Part, err error) *PartIterator {
+	return &PartIterator{
+		items:     parts,
+		position:  -1,
+		completed: len(parts) == 0,
+		err:       err,
+	}
+}
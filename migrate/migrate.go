@@ -0,0 +1,258 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package migrate rewrites the objects of a bucket, so objects uploaded with
+// legacy encryption parameters or segment sizes get the ones of the current
+// configuration.
+//
+// Objects are downloaded from the source project and uploaded again to the
+// same key with the target project. The target project should be opened
+// with the same access grant, but with the new configuration, e.g. a
+// different uplink.Config.ContentCipher.
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/uplink"
+	"storj.io/uplink/internal/expose"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for this package.
+var Error = errs.Class("migrate")
+
+// ErrVerifyFailed is returned for objects whose rewritten content doesn't
+// match the original content.
+var ErrVerifyFailed = errs.Class("migrate: verification failed")
+
+// defaultWorkers is the default number of objects migrated in parallel.
+const defaultWorkers = 4
+
+// Options configures a migration.
+type Options struct {
+	// Prefix limits the migration to objects with the prefix.
+	Prefix string
+	// Workers is the number of objects migrated in parallel. Zero means 4.
+	Workers int
+	// Verify downloads every rewritten object again and compares it with
+	// the original content.
+	Verify bool
+	// ProgressFile records the keys of the migrated objects. Objects already
+	// recorded in it are skipped, so an interrupted migration can be
+	// resumed by running it again with the same file.
+	// If ProgressFile is empty, progress is not recorded.
+	ProgressFile string
+}
+
+// Result describes the outcome of a migration.
+type Result struct {
+	// Migrated is the number of rewritten objects.
+	Migrated int64
+	// Skipped is the number of objects skipped because they were already
+	// recorded in the progress file.
+	Skipped int64
+	// Failed contains the objects that couldn't be migrated.
+	Failed []Failure
+}
+
+// Failure describes an object that couldn't be migrated.
+type Failure struct {
+	Key string
+	Err error
+}
+
+// Bucket migrates all objects in bucket by downloading them from source and
+// uploading them again with target. Custom metadata, expiration and
+// compression of the objects are preserved.
+//
+// The returned result is never nil. The error is non-nil when the migration
+// couldn't run, e.g. listing failed, or some objects couldn't be migrated.
+func Bucket(ctx context.Context, source, target *uplink.Project, bucket string, options *Options) (result *Result, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result = &Result{}
+
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = defaultWorkers
+	}
+
+	progress, err := openProgress(opts.ProgressFile)
+	if err != nil {
+		return result, Error.Wrap(err)
+	}
+	defer func() { err = errs.Combine(err, Error.Wrap(progress.Close())) }()
+
+	var mu sync.Mutex
+	var group errgroup.Group
+	group.SetLimit(opts.Workers)
+
+	objects := source.ListObjects(ctx, bucket, &uplink.ListObjectsOptions{
+		Prefix:    opts.Prefix,
+		Recursive: true,
+		System:    true,
+		Custom:    true,
+	})
+	for objects.Next() {
+		object := objects.Item()
+		if progress.Done(object.Key) {
+			result.Skipped++
+			continue
+		}
+
+		group.Go(func() error {
+			err := migrateObject(ctx, source, target, bucket, object, opts.Verify)
+			if err == nil {
+				err = progress.Record(object.Key)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed = append(result.Failed, Failure{Key: object.Key, Err: err})
+			} else {
+				result.Migrated++
+			}
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	if err := objects.Err(); err != nil {
+		return result, Error.Wrap(err)
+	}
+	if err := ctx.Err(); err != nil {
+		return result, Error.Wrap(err)
+	}
+	if len(result.Failed) > 0 {
+		return result, Error.New("failed to migrate %d objects, first error: %v", len(result.Failed), result.Failed[0].Err)
+	}
+	return result, nil
+}
+
+// migrateObject rewrites a single object.
+func migrateObject(ctx context.Context, source, target *uplink.Project, bucket string, object *uplink.Object, verify bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	hash := sha256.New()
+	if _, err := expose.ReuploadObject(ctx, source, bucket, object, target, bucket, object.Key, hash); err != nil {
+		return err
+	}
+
+	if verify {
+		return verifyObject(ctx, target, bucket, object.Key, hash.Sum(nil))
+	}
+	return nil
+}
+
+// verifyObject checks that the content of the object has the expected hash.
+func verifyObject(ctx context.Context, project *uplink.Project, bucket, key string, expected []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	download, err := project.DownloadObject(ctx, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, download.Close()) }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, download); err != nil {
+		return err
+	}
+	if !bytes.Equal(hash.Sum(nil), expected) {
+		return ErrVerifyFailed.New("%q", key)
+	}
+	return nil
+}
+
+// progressFile records the keys of migrated objects, one per line.
+type progressFile struct {
+	done map[string]struct{}
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openProgress loads the keys recorded in path and opens it for recording
+// more. An empty path records nothing.
+func openProgress(path string) (*progressFile, error) {
+	progress := &progressFile{done: map[string]struct{}{}}
+	if path == "" {
+		return progress, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 64*1024)
+	for scanner.Scan() {
+		// keys may contain newlines, so they are stored quoted.
+		key, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			// the last line may be incomplete after a crash.
+			continue
+		}
+		progress.done[key] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	progress.file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	// terminate an incomplete last line, so it doesn't corrupt the next key.
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		if _, err := progress.file.WriteString("\n"); err != nil {
+			return nil, errs.Combine(err, progress.file.Close())
+		}
+	}
+	return progress, nil
+}
+
+// Done returns whether key was migrated before.
+func (progress *progressFile) Done(key string) bool {
+	_, ok := progress.done[key]
+	return ok
+}
+
+// Record records that key was migrated.
+func (progress *progressFile) Record(key string) error {
+	if progress.file == nil {
+		return nil
+	}
+
+	progress.mu.Lock()
+	defer progress.mu.Unlock()
+	_, err := progress.file.WriteString(strconv.Quote(key) + "\n")
+	return Error.Wrap(err)
+}
+
+// Close closes the progress file.
+func (progress *progressFile) Close() error {
+	if progress.file == nil {
+		return nil
+	}
+	return progress.file.Close()
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress")
+
+	progress, err := openProgress(path)
+	require.NoError(t, err)
+	require.False(t, progress.Done("a"))
+	require.NoError(t, progress.Record("a"))
+	require.NoError(t, progress.Record("with\nnewline"))
+	require.NoError(t, progress.Close())
+
+	// simulate a crash while writing the last line
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`"incompl`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	progress, err = openProgress(path)
+	require.NoError(t, err)
+	require.True(t, progress.Done("a"))
+	require.True(t, progress.Done("with\nnewline"))
+	require.False(t, progress.Done("b"))
+	require.NoError(t, progress.Record("b"))
+	require.NoError(t, progress.Close())
+
+	progress, err = openProgress(path)
+	require.NoError(t, err)
+	require.True(t, progress.Done("b"))
+	require.NoError(t, progress.Close())
+
+	// without a path nothing is recorded
+	progress, err = openProgress("")
+	require.NoError(t, err)
+	require.NoError(t, progress.Record("a"))
+	require.False(t, progress.Done("a"))
+	require.NoError(t, progress.Close())
+}
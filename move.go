@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/rand"
 	"strings"
+	"time"
 
 	"github.com/zeebo/errs"
 
@@ -17,13 +18,18 @@ import (
 
 // MoveObjectOptions options for MoveObject method.
 type MoveObjectOptions struct {
-	// may contain StreamID and Version in the future
+	// Timeout overrides Config.MetainfoTimeout for the requests of this call.
+	Timeout time.Duration
 }
 
 // MoveObject moves object to a different bucket or/and key.
 func (project *Project) MoveObject(ctx context.Context, oldbucket, oldkey, newbucket, newkey string, options *MoveObjectOptions) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if options != nil && options.Timeout != 0 {
+		ctx = metaclient.WithTimeout(ctx, options.Timeout)
+	}
+
 	err = validateMoveCopyInput(oldbucket, oldkey, newbucket, newkey)
 	if err != nil {
 		return packageError.Wrap(err)
@@ -45,6 +51,9 @@ func (project *Project) MoveObject(ctx context.Context, oldbucket, oldkey, newbu
 	}
 	defer func() { err = errs.Combine(err, metainfoClient.Close()) }()
 
+	project.recentStats.forget(oldbucket, oldkey)
+	project.recentStats.forget(newbucket, newkey)
+
 	response, err := metainfoClient.BeginMoveObject(ctx, metaclient.BeginMoveObjectParams{
 		Bucket:                []byte(oldbucket),
 		EncryptedObjectKey:    []byte(oldEncKey.Raw()),
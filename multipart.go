@@ -19,7 +19,6 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/storj"
 	"storj.io/eventkit"
-	"storj.io/uplink/private/eestream/scheduler"
 	"storj.io/uplink/private/metaclient"
 	"storj.io/uplink/private/storage/streams"
 	"storj.io/uplink/private/stream"
@@ -111,12 +110,18 @@ func (project *Project) CommitUpload(ctx context.Context, bucket, key, uploadID
 		opts = &CommitUploadOptions{}
 	}
 
+	if err := opts.CustomMetadata.Verify(); err != nil {
+		return nil, packageError.Wrap(err)
+	}
+
 	metainfoDB, err := project.dialMetainfoDB(ctx)
 	if err != nil {
 		return nil, packageError.Wrap(err)
 	}
 	defer func() { err = errs.Combine(err, metainfoDB.Close()) }()
 
+	project.recentStats.forget(bucket, key)
+
 	mObject, err := metainfoDB.CommitObject(ctx, bucket, key, uploadID, opts.CustomMetadata, project.encryptionParameters)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
@@ -158,6 +163,9 @@ func (project *Project) UploadPart(ctx context.Context, bucket, key, uploadID st
 	case partNumber >= math.MaxInt32:
 		return nil, packageError.New("partNumber should be less than max(int32)")
 	}
+	if err := project.inflight.err(); err != nil {
+		return nil, err
+	}
 
 	decodedStreamID, version, err := base58.CheckDecode(uploadID)
 	if err != nil || version != 1 {
@@ -180,7 +188,7 @@ func (project *Project) UploadPart(ctx context.Context, bucket, key, uploadID st
 	if project.concurrentSegmentUploadConfig == nil {
 		upload.upload = stream.NewUploadPart(ctx, bucket, key, decodedStreamID, partNumber, upload.eTagCh, streams)
 	} else {
-		sched := scheduler.New(project.concurrentSegmentUploadConfig.SchedulerOptions)
+		sched := project.uploadScheduler()
 		u, err := streams.UploadPart(ctx, bucket, key, decodedStreamID, int32(partNumber), upload.eTagCh, sched)
 		if err != nil {
 			return nil, convertKnownErrors(err, bucket, key)
@@ -189,6 +197,17 @@ func (project *Project) UploadPart(ctx context.Context, bucket, key, uploadID st
 	}
 
 	upload.tracker = project.tracker.Child("upload-part", 1)
+
+	upload.mu.Lock()
+	upload.untrack, err = project.inflight.add(inflightOp{
+		cancel: upload.cancel,
+		abort:  upload.Abort,
+	})
+	upload.mu.Unlock()
+	if err != nil {
+		upload.cancel()
+		return nil, errs.Combine(err, upload.upload.Abort(), upload.streams.Close(), upload.tracker.Close())
+	}
 	return upload, nil
 }
 
@@ -346,6 +365,7 @@ type PartUpload struct {
 	part    *Part
 	streams *streams.Store
 	eTagCh  chan []byte
+	untrack func()
 
 	stats operationStats
 	task  func(*error)
@@ -405,6 +425,9 @@ func (upload *PartUpload) Commit() error {
 	}
 
 	upload.closed = true
+	// the upload is only untracked once it's committed, so Shutdown waits
+	// for the commit in progress.
+	defer upload.stopTracking()
 
 	// ETag must not be sent after a call to commit. The upload code waits on
 	// the channel before committing the last segment. Closing the channel
@@ -442,6 +465,7 @@ func (upload *PartUpload) Abort() error {
 	}
 
 	upload.aborted = true
+	defer upload.stopTracking()
 	upload.cancel()
 
 	err := errs.Combine(
@@ -456,6 +480,14 @@ func (upload *PartUpload) Abort() error {
 	return convertKnownErrors(err, upload.bucket, upload.key)
 }
 
+// stopTracking removes the upload from the operations aborted by
+// Project.Shutdown.
+func (upload *PartUpload) stopTracking() {
+	if upload.untrack != nil {
+		upload.untrack()
+	}
+}
+
 // Info returns the last information about the uploaded part.
 func (upload *PartUpload) Info() *Part {
 	if meta := upload.upload.Meta(); meta != nil {
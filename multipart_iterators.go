@@ -202,6 +202,10 @@ func (parts *PartIterator) Next() bool {
 		return false
 	}
 
+	if parts.completed {
+		return false
+	}
+
 	if len(parts.items) == 0 {
 		more := parts.loadNext()
 		parts.completed = !more
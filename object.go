@@ -7,6 +7,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/zeebo/errs"
 
+	"storj.io/common/pb"
 	"storj.io/uplink/private/metaclient"
 )
 
@@ -33,6 +36,9 @@ type Object struct {
 	Custom CustomMetadata
 
 	version []byte
+	// stream is the information needed to download the object without
+	// requesting it again. It's only set by StatObject.
+	stream *metaclient.Object
 }
 
 // SystemMetadata contains information about the object that cannot be changed directly.
@@ -59,7 +65,47 @@ func (meta CustomMetadata) Clone() CustomMetadata {
 	return r
 }
 
-// Verify verifies whether CustomMetadata contains only "utf-8".
+// MaxCustomMetadataSize is the maximum size of custom metadata accepted by
+// satellites with the default configuration, as returned by
+// CustomMetadata.Size. Satellites may be configured with a different limit.
+const MaxCustomMetadataSize = 2 * 1024
+
+// CustomMetadataError is returned when custom metadata is invalid.
+type CustomMetadataError struct {
+	// Invalid describes the invalid key-value pairs.
+	Invalid []string
+	// Size is the size of the metadata, when it exceeds MaxSize.
+	Size int
+	// MaxSize is the limit passed to CustomMetadata.VerifySize.
+	MaxSize int
+}
+
+// Error implements error.
+func (err *CustomMetadataError) Error() string {
+	var problems []string
+	if len(err.Invalid) > 0 {
+		problems = append(problems, fmt.Sprintf("invalid pairs %v", err.Invalid))
+	}
+	if err.Size > err.MaxSize {
+		problems = append(problems, fmt.Sprintf("size %d exceeds maximum %d", err.Size, err.MaxSize))
+	}
+	return "custom metadata: " + strings.Join(problems, ", ")
+}
+
+// Size returns the size of the custom metadata after it's encoded. The
+// satellite limits the size of the encrypted stream information, which
+// contains the encoded custom metadata together with the segment sizes, so
+// the stored size is a few bytes larger.
+func (meta CustomMetadata) Size() int {
+	metadata, err := pb.Marshal(&pb.SerializableMeta{UserDefined: meta})
+	if err != nil {
+		return math.MaxInt
+	}
+	return len(metadata)
+}
+
+// Verify verifies whether CustomMetadata contains only "utf-8", no zero bytes
+// and no empty keys. The returned error is a *CustomMetadataError.
 func (meta CustomMetadata) Verify() error {
 	var invalid []string
 	for k, v := range meta {
@@ -73,17 +119,46 @@ func (meta CustomMetadata) Verify() error {
 			invalid = append(invalid, "empty key")
 		}
 	}
+	sort.Strings(invalid)
 
 	if len(invalid) > 0 {
-		return errs.New("invalid pairs %v", invalid)
+		return &CustomMetadataError{Invalid: invalid}
 	}
 
 	return nil
 }
 
+// VerifySize verifies whether the size of CustomMetadata, as returned by
+// Size, doesn't exceed maxSize, e.g. MaxCustomMetadataSize. Uploads don't
+// check the size, since only the satellite knows its limit. The returned error
+// is a *CustomMetadataError.
+func (meta CustomMetadata) VerifySize(maxSize int) error {
+	if size := meta.Size(); size > maxSize {
+		return &CustomMetadataError{Size: size, MaxSize: maxSize}
+	}
+	return nil
+}
+
+// StatObjectOptions defines options for StatObjectWithOptions.
+type StatObjectOptions struct {
+	// Timeout overrides Config.MetainfoTimeout for the requests of this call.
+	Timeout time.Duration
+}
+
 // StatObject returns information about an object at the specific key.
 func (project *Project) StatObject(ctx context.Context, bucket, key string) (info *Object, err error) {
+	return project.StatObjectWithOptions(ctx, bucket, key, nil)
+}
+
+// StatObjectWithOptions returns information about an object at the specific
+// key, like StatObject, with options.
+func (project *Project) StatObjectWithOptions(ctx context.Context, bucket, key string, options *StatObjectOptions) (info *Object, err error) {
 	defer mon.Task()(&ctx)(&err)
+	defer project.instrument("StatObject")(&err)
+
+	if options != nil && options.Timeout != 0 {
+		ctx = metaclient.WithTimeout(ctx, options.Timeout)
+	}
 
 	db, err := project.dialMetainfoDB(ctx)
 	if err != nil {
@@ -95,8 +170,13 @@ func (project *Project) StatObject(ctx context.Context, bucket, key string) (inf
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
 	}
+	project.recentStats.add(bucket, key, &obj)
 
-	return convertObject(&obj), nil
+	info = convertObject(&obj)
+	if info != nil {
+		info.stream = &obj
+	}
+	return info, nil
 }
 
 // DeleteObject deletes the object at the specific key.
@@ -111,6 +191,8 @@ func (project *Project) DeleteObject(ctx context.Context, bucket, key string) (d
 	}
 	defer func() { err = errs.Combine(err, db.Close()) }()
 
+	project.recentStats.forget(bucket, key)
+
 	obj, err := db.DeleteObject(ctx, bucket, key, nil)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
@@ -128,12 +210,18 @@ type UploadObjectMetadataOptions struct {
 func (project *Project) UpdateObjectMetadata(ctx context.Context, bucket, key string, newMetadata CustomMetadata, options *UploadObjectMetadataOptions) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	if err := newMetadata.Verify(); err != nil {
+		return packageError.Wrap(err)
+	}
+
 	db, err := project.dialMetainfoDB(ctx)
 	if err != nil {
 		return convertKnownErrors(err, bucket, key)
 	}
 	defer func() { err = errs.Combine(err, db.Close()) }()
 
+	project.recentStats.forget(bucket, key)
+
 	err = db.UpdateObjectMetadata(ctx, bucket, key, newMetadata.Clone())
 	if err != nil {
 		return convertKnownErrors(err, bucket, key)
@@ -4,6 +4,7 @@
 package uplink_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -37,3 +38,29 @@ func TestCustomMetadata_Verify_Invalid(t *testing.T) {
 		require.Error(t, meta.Verify(), meta)
 	}
 }
+
+func TestCustomMetadata_VerifySize(t *testing.T) {
+	small := uplink.CustomMetadata{"key": "value"}
+	require.NoError(t, small.VerifySize(uplink.MaxCustomMetadataSize))
+	require.Less(t, small.Size(), uplink.MaxCustomMetadataSize)
+
+	// the size is only checked by VerifySize.
+	large := uplink.CustomMetadata{"key": strings.Repeat("A", uplink.MaxCustomMetadataSize)}
+	require.NoError(t, large.Verify())
+	err := large.VerifySize(uplink.MaxCustomMetadataSize)
+	require.Error(t, err)
+
+	var metadataErr *uplink.CustomMetadataError
+	require.ErrorAs(t, err, &metadataErr)
+	require.Empty(t, metadataErr.Invalid)
+	require.Equal(t, large.Size(), metadataErr.Size)
+	require.Equal(t, uplink.MaxCustomMetadataSize, metadataErr.MaxSize)
+
+	// the limit is up to the caller.
+	require.NoError(t, large.VerifySize(2*uplink.MaxCustomMetadataSize))
+	require.Error(t, small.VerifySize(small.Size()-1))
+
+	invalid := uplink.CustomMetadata{"": "value"}
+	require.ErrorAs(t, invalid.Verify(), &metadataErr)
+	require.Equal(t, []string{"empty key"}, metadataErr.Invalid)
+}
@@ -5,6 +5,8 @@ package uplink
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/zeebo/errs"
 
@@ -28,8 +30,45 @@ type ListObjectsOptions struct {
 	System bool
 	// Custom includes CustomMetadata in the results.
 	Custom bool
+
+	// CustomMetadataFilter only includes objects whose custom metadata
+	// contains all of the key-value pairs. Custom metadata is encrypted, so
+	// the filtering happens on the client after each page is listed.
+	// Prefixes are not filtered.
+	CustomMetadataFilter map[string]string
+
+	// Timeout overrides Config.MetainfoTimeout for the requests of this
+	// listing.
+	Timeout time.Duration
+
+	// SortBy sorts the listed objects. The satellite can't sort by
+	// encrypted keys or system metadata, so all pages are listed before the
+	// first item is returned. All listed items are kept in memory, so the
+	// listing fails when there are more than MaxSortedObjects. Prefixes are
+	// listed before objects when sorting by creation time or size.
+	SortBy SortBy
+	// SortDescending reverses the order of SortBy.
+	SortDescending bool
 }
 
+// MaxSortedObjects is the maximum number of items a listing can sort.
+const MaxSortedObjects = 100000
+
+// SortBy defines the order of listed objects.
+type SortBy int
+
+const (
+	// SortByNone lists the objects in the order returned by the satellite,
+	// page by page.
+	SortByNone SortBy = iota
+	// SortByKey sorts the objects by key.
+	SortByKey
+	// SortByCreated sorts the objects by creation time.
+	SortByCreated
+	// SortBySize sorts the objects by content length.
+	SortBySize
+)
+
 // ListObjects returns an iterator over the objects.
 func (project *Project) ListObjects(ctx context.Context, bucket string, options *ListObjectsOptions) *ObjectIterator {
 	defer mon.Task()(&ctx)(nil)
@@ -45,6 +84,15 @@ func (project *Project) ListObjects(ctx context.Context, bucket string, options
 		opts.Recursive = options.Recursive
 		opts.IncludeCustomMetadata = options.Custom
 		opts.IncludeSystemMetadata = options.System
+		if len(options.CustomMetadataFilter) > 0 {
+			opts.IncludeCustomMetadata = true
+		}
+		if options.SortBy == SortByCreated || options.SortBy == SortBySize {
+			opts.IncludeSystemMetadata = true
+		}
+		if options.Timeout != 0 {
+			ctx = metaclient.WithTimeout(ctx, options.Timeout)
+		}
 	}
 
 	opts.Limit = testuplink.GetListLimit(ctx)
@@ -79,6 +127,30 @@ type ObjectIterator struct {
 // Next prepares next Object for reading.
 // It returns false if the end of the iteration is reached and there are no more objects, or if there is an error.
 func (objects *ObjectIterator) Next() bool {
+	for objects.next() {
+		if objects.matchesFilter() {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFilter returns whether the current item matches
+// CustomMetadataFilter.
+func (objects *ObjectIterator) matchesFilter() bool {
+	item := objects.item()
+	if item == nil || item.IsPrefix {
+		return true
+	}
+	for key, value := range objects.objOptions.CustomMetadataFilter {
+		if actual, ok := item.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (objects *ObjectIterator) next() bool {
 	if objects.err != nil {
 		objects.completed = true
 		return false
@@ -106,7 +178,13 @@ func (objects *ObjectIterator) Next() bool {
 }
 
 func (objects *ObjectIterator) loadNext() bool {
-	ok, err := objects.tryLoadNext()
+	var ok bool
+	var err error
+	if objects.objOptions.SortBy != SortByNone {
+		ok, err = objects.tryLoadSorted()
+	} else {
+		ok, err = objects.tryLoadNext()
+	}
 	if err != nil {
 		objects.err = err
 		return false
@@ -115,6 +193,8 @@ func (objects *ObjectIterator) loadNext() bool {
 }
 
 func (objects *ObjectIterator) tryLoadNext() (ok bool, err error) {
+	defer objects.project.instrument("ListObjects")(&err)
+
 	db, err := objects.project.dialMetainfoDB(objects.ctx)
 	if err != nil {
 		return false, convertKnownErrors(err, objects.bucket.Name, "")
@@ -133,6 +213,55 @@ func (objects *ObjectIterator) tryLoadNext() (ok bool, err error) {
 	return len(list.Items) > 0, nil
 }
 
+// tryLoadSorted lists all pages and sorts the items as requested by
+// ListObjectsOptions.SortBy.
+func (objects *ObjectIterator) tryLoadSorted() (ok bool, err error) {
+	var items []metaclient.Object
+	for {
+		if _, err := objects.tryLoadNext(); err != nil {
+			return false, err
+		}
+		items = append(items, objects.list.Items...)
+		if len(items) > MaxSortedObjects {
+			return false, packageError.New("cannot sort more than %d objects", MaxSortedObjects)
+		}
+		if !objects.list.More {
+			break
+		}
+	}
+
+	sortObjects(items, objects.objOptions.SortBy, objects.objOptions.SortDescending)
+	objects.list = &metaclient.ObjectList{Items: items}
+	objects.position = 0
+	return len(items) > 0, nil
+}
+
+// sortObjects sorts items by the given order. Items that compare equal are
+// sorted by key.
+func sortObjects(items []metaclient.Object, by SortBy, descending bool) {
+	less := func(a, b *metaclient.Object) bool {
+		if by == SortByCreated || by == SortBySize {
+			if a.IsPrefix != b.IsPrefix {
+				return a.IsPrefix
+			}
+			switch {
+			case by == SortByCreated && !a.Created.Equal(b.Created):
+				return a.Created.Before(b.Created)
+			case by == SortBySize && a.Size != b.Size:
+				return a.Size < b.Size
+			}
+		}
+		return a.Path < b.Path
+	}
+
+	sort.SliceStable(items, func(i, k int) bool {
+		if descending {
+			return less(&items[k], &items[i])
+		}
+		return less(&items[i], &items[k])
+	})
+}
+
 // Err returns error, if one happened during iteration.
 func (objects *ObjectIterator) Err() error {
 	return packageError.Wrap(objects.err)
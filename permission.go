@@ -0,0 +1,134 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"time"
+)
+
+// PermissionBuilder builds a Permission and the prefixes it's restricted to,
+// starting from no allowed actions. Use it with Access.Share, or call Share
+// directly.
+//
+// The presets ReadOnlyPrefix, WriteOnlyDropbox and ListOnly create builders
+// for common least-privilege grants, which can be further restricted.
+type PermissionBuilder struct {
+	permission Permission
+	prefixes   []SharePrefix
+}
+
+// NewPermissionBuilder returns a builder that allows nothing and isn't
+// restricted to any prefixes.
+func NewPermissionBuilder() *PermissionBuilder {
+	return &PermissionBuilder{}
+}
+
+// ReadOnlyPrefix returns a builder that allows downloading and listing
+// objects under prefix in bucket.
+func ReadOnlyPrefix(bucket, prefix string) *PermissionBuilder {
+	return NewPermissionBuilder().AllowDownload().AllowList().Prefix(bucket, prefix)
+}
+
+// WriteOnlyDropbox returns a builder that only allows uploading new objects
+// under prefix in bucket. Existing objects can't be read, listed, deleted or
+// overwritten.
+func WriteOnlyDropbox(bucket, prefix string) *PermissionBuilder {
+	return NewPermissionBuilder().AllowUpload().Prefix(bucket, prefix)
+}
+
+// ListOnly returns a builder that only allows listing objects under prefix in
+// bucket. Object content can't be downloaded.
+func ListOnly(bucket, prefix string) *PermissionBuilder {
+	return NewPermissionBuilder().AllowList().Prefix(bucket, prefix)
+}
+
+// AllowDownload allows downloading the object's content. See
+// Permission.AllowDownload.
+func (builder *PermissionBuilder) AllowDownload() *PermissionBuilder {
+	builder.permission.AllowDownload = true
+	return builder
+}
+
+// AllowUpload allows creating buckets and uploading new objects. See
+// Permission.AllowUpload.
+func (builder *PermissionBuilder) AllowUpload() *PermissionBuilder {
+	builder.permission.AllowUpload = true
+	return builder
+}
+
+// AllowList allows listing buckets and objects. See Permission.AllowList.
+func (builder *PermissionBuilder) AllowList() *PermissionBuilder {
+	builder.permission.AllowList = true
+	return builder
+}
+
+// AllowDelete allows deleting buckets and objects. Together with AllowUpload
+// it allows overwriting objects. See Permission.AllowDelete.
+func (builder *PermissionBuilder) AllowDelete() *PermissionBuilder {
+	builder.permission.AllowDelete = true
+	return builder
+}
+
+// NotBefore restricts the access grant to be valid only from t.
+func (builder *PermissionBuilder) NotBefore(t time.Time) *PermissionBuilder {
+	builder.permission.NotBefore = t
+	return builder
+}
+
+// NotAfter restricts the access grant to be valid only until t.
+func (builder *PermissionBuilder) NotAfter(t time.Time) *PermissionBuilder {
+	builder.permission.NotAfter = t
+	return builder
+}
+
+// MaxObjectTTL restricts the time-to-live of uploaded objects to ttl.
+func (builder *PermissionBuilder) MaxObjectTTL(ttl time.Duration) *PermissionBuilder {
+	builder.permission.MaxObjectTTL = &ttl
+	return builder
+}
+
+// Prefix restricts the access grant to prefix in bucket. It can be called
+// multiple times to allow access to several prefixes.
+func (builder *PermissionBuilder) Prefix(bucket, prefix string) *PermissionBuilder {
+	builder.prefixes = append(builder.prefixes, SharePrefix{
+		Bucket: bucket,
+		Prefix: prefix,
+	})
+	return builder
+}
+
+// Build returns the permission and the prefixes to use with Access.Share.
+//
+// It fails when no action is allowed, or the validity period is empty.
+func (builder *PermissionBuilder) Build() (Permission, []SharePrefix, error) {
+	permission := builder.permission
+	if !permission.AllowDownload && !permission.AllowUpload && !permission.AllowList && !permission.AllowDelete {
+		return Permission{}, nil, packageError.New("permission doesn't allow any action")
+	}
+	if !permission.NotBefore.IsZero() && !permission.NotAfter.IsZero() && !permission.NotBefore.Before(permission.NotAfter) {
+		return Permission{}, nil, packageError.New("permission NotBefore (%v) must be before NotAfter (%v)", permission.NotBefore, permission.NotAfter)
+	}
+	for _, prefix := range builder.prefixes {
+		if prefix.Bucket == "" {
+			return Permission{}, nil, packageError.New("prefix %q has no bucket", prefix.Prefix)
+		}
+	}
+
+	prefixes := append([]SharePrefix(nil), builder.prefixes...)
+	return permission, prefixes, nil
+}
+
+// Share creates a new access grant from access with the built permission and
+// prefixes. See Access.Share.
+func (builder *PermissionBuilder) Share(access *Access) (*Access, error) {
+	if access == nil {
+		return nil, packageError.New("access grant is nil")
+	}
+
+	permission, prefixes, err := builder.Build()
+	if err != nil {
+		return nil, err
+	}
+	return access.Share(permission, prefixes...)
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink"
+)
+
+func TestPermissionBuilder_Presets(t *testing.T) {
+	for _, tt := range []struct {
+		builder  *uplink.PermissionBuilder
+		expected uplink.Permission
+	}{
+		{
+			builder:  uplink.ReadOnlyPrefix("bucket", "prefix/"),
+			expected: uplink.Permission{AllowDownload: true, AllowList: true},
+		},
+		{
+			builder:  uplink.WriteOnlyDropbox("bucket", "prefix/"),
+			expected: uplink.Permission{AllowUpload: true},
+		},
+		{
+			builder:  uplink.ListOnly("bucket", "prefix/"),
+			expected: uplink.Permission{AllowList: true},
+		},
+	} {
+		permission, prefixes, err := tt.builder.Build()
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, permission)
+		require.Equal(t, []uplink.SharePrefix{{Bucket: "bucket", Prefix: "prefix/"}}, prefixes)
+	}
+}
+
+func TestPermissionBuilder(t *testing.T) {
+	now := time.Now()
+	ttl := time.Hour
+
+	permission, prefixes, err := uplink.NewPermissionBuilder().
+		AllowUpload().
+		AllowDelete().
+		NotBefore(now).
+		NotAfter(now.Add(time.Hour)).
+		MaxObjectTTL(ttl).
+		Prefix("a", "").
+		Prefix("b", "c/").
+		Build()
+	require.NoError(t, err)
+	require.Equal(t, uplink.Permission{
+		AllowUpload:  true,
+		AllowDelete:  true,
+		NotBefore:    now,
+		NotAfter:     now.Add(time.Hour),
+		MaxObjectTTL: &ttl,
+	}, permission)
+	require.Equal(t, []uplink.SharePrefix{{Bucket: "a"}, {Bucket: "b", Prefix: "c/"}}, prefixes)
+
+	_, _, err = uplink.NewPermissionBuilder().Build()
+	require.Error(t, err)
+
+	_, _, err = uplink.NewPermissionBuilder().AllowList().NotBefore(now).NotAfter(now).Build()
+	require.Error(t, err)
+
+	_, _, err = uplink.NewPermissionBuilder().AllowList().Prefix("", "prefix/").Build()
+	require.Error(t, err)
+
+	_, err = uplink.ReadOnlyPrefix("bucket", "").Share(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"storj.io/common/pb"
+	"storj.io/uplink/private/piecestore"
+)
+
+// PieceHash selects the algorithm used to hash the pieces uploaded to the
+// storage nodes. The storage nodes sign the hash with the algorithm the
+// uplink requested and the uplink verifies it.
+type PieceHash int
+
+const (
+	// PieceHashDefault uses the default algorithm, which is BLAKE3.
+	PieceHashDefault PieceHash = iota
+	// PieceHashSHA256 uses SHA-256, which is supported by all storage nodes.
+	PieceHashSHA256
+	// PieceHashBLAKE3 uses BLAKE3, which is considerably faster on CPUs
+	// without SHA extensions.
+	PieceHashBLAKE3
+)
+
+// withPieceHash returns a context, which makes the piece uploads use the
+// algorithm.
+func (hash PieceHash) withPieceHash(ctx context.Context) (context.Context, error) {
+	switch hash {
+	case PieceHashDefault:
+		return ctx, nil
+	case PieceHashSHA256:
+		return piecestore.WithPieceHashAlgo(ctx, pb.PieceHashAlgorithm_SHA256), nil
+	case PieceHashBLAKE3:
+		return piecestore.WithPieceHashAlgo(ctx, pb.PieceHashAlgorithm_BLAKE3), nil
+	default:
+		return ctx, packageError.New("unknown piece hash %d", hash)
+	}
+}
@@ -74,6 +74,38 @@ func (buckets *Iterator) Item() *Bucket {
 	}
 }
 
+// GetBucketAttribution returns the attribution of the bucket, i.e. the user
+// agent of the project that created it.
+func GetBucketAttribution(ctx context.Context, project *uplink.Project, bucketName string) (_ string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucketName == "" {
+		return "", convertKnownErrors(metaclient.ErrNoBucket.New(""), bucketName, "")
+	}
+
+	metainfoClient, err := dialMetainfoClient(ctx, project)
+	if err != nil {
+		return "", convertKnownErrors(err, bucketName, "")
+	}
+	defer func() { err = errs.Combine(err, metainfoClient.Close()) }()
+
+	// the satellite returns the attribution only when listing buckets.
+	list, err := metainfoClient.ListBuckets(ctx, metaclient.ListBucketsParams{
+		ListOpts: metaclient.BucketListOptions{
+			Cursor:    bucketName,
+			Direction: metaclient.Forward,
+			Limit:     1,
+		},
+	})
+	if err != nil {
+		return "", convertKnownErrors(err, bucketName, "")
+	}
+	if len(list.Items) == 0 || list.Items[0].Name != bucketName {
+		return "", convertKnownErrors(metaclient.ErrBucketNotFound.New("%s", bucketName), bucketName, "")
+	}
+	return list.Items[0].Attribution, nil
+}
+
 // GetBucketLocation returns bucket location.
 func GetBucketLocation(ctx context.Context, project *uplink.Project, bucketName string) (_ string, err error) {
 	defer mon.Task()(&ctx)(&err)
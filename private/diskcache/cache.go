@@ -0,0 +1,214 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package diskcache implements an on-disk cache of object contents.
+package diskcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for this package.
+var Error = errs.Class("diskcache")
+
+// tempSuffix is the suffix of entries that are still being written.
+const tempSuffix = ".tmp"
+
+// Cache is an on-disk cache of object contents. Entries are identified by
+// a key, which should include everything that identifies the content, so
+// stale entries are never returned. Stale entries are removed by TTL or
+// when the cache exceeds its maximum size.
+type Cache struct {
+	dir     string
+	maxSize int64
+	ttl     time.Duration
+
+	mu sync.Mutex
+}
+
+// New creates a new cache in dir. When maxSize is positive, the oldest
+// entries are removed when the total size exceeds it. When ttl is positive,
+// entries older than ttl are not returned.
+func New(dir string, maxSize int64, ttl time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		ttl:     ttl,
+	}, nil
+}
+
+// Key returns a cache key for the given parts.
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		_, _ = hash.Write([]byte(part))
+		_, _ = hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Open opens the entry with the specified key. It returns nil when the
+// entry does not exist or has expired.
+func (cache *Cache) Open(key string) (_ *os.File, err error) {
+	path := filepath.Join(cache.dir, key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			mon.Event("diskcache_miss")
+			return nil, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	if cache.expired(info, time.Now()) {
+		mon.Event("diskcache_expired")
+		return nil, Error.Wrap(ignoreNotExist(os.Remove(path)))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			mon.Event("diskcache_miss")
+			return nil, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	mon.Event("diskcache_hit")
+	return file, nil
+}
+
+// Create starts writing the entry with the specified key. The entry becomes
+// visible after Commit.
+func (cache *Cache) Create(key string) (*Entry, error) {
+	file, err := os.CreateTemp(cache.dir, key+"-*"+tempSuffix)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Entry{
+		cache: cache,
+		key:   key,
+		file:  file,
+	}, nil
+}
+
+// expired returns whether the entry described by info is past its TTL.
+func (cache *Cache) expired(info fs.FileInfo, now time.Time) bool {
+	return cache.ttl > 0 && now.Sub(info.ModTime()) > cache.ttl
+}
+
+// evict removes expired entries and the oldest entries exceeding the
+// maximum size.
+func (cache *Cache) evict() (err error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	dirEntries, err := os.ReadDir(cache.dir)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	now := time.Now()
+
+	var group errs.Group
+	var total int64
+	var entries []fs.FileInfo
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || strings.HasSuffix(dirEntry.Name(), tempSuffix) {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				group.Add(err)
+			}
+			continue
+		}
+		if cache.expired(info, now) {
+			group.Add(ignoreNotExist(os.Remove(filepath.Join(cache.dir, info.Name()))))
+			continue
+		}
+		total += info.Size()
+		entries = append(entries, info)
+	}
+
+	if cache.maxSize > 0 && total > cache.maxSize {
+		sort.Slice(entries, func(i, k int) bool {
+			return entries[i].ModTime().Before(entries[k].ModTime())
+		})
+		for _, info := range entries {
+			if total <= cache.maxSize {
+				break
+			}
+			group.Add(ignoreNotExist(os.Remove(filepath.Join(cache.dir, info.Name()))))
+			total -= info.Size()
+			mon.Event("diskcache_evicted")
+		}
+	}
+
+	return Error.Wrap(group.Err())
+}
+
+// Entry is a cache entry that is being written.
+type Entry struct {
+	cache *Cache
+	key   string
+	file  *os.File
+	done  bool
+}
+
+// Write implements io.Writer.
+func (entry *Entry) Write(p []byte) (int, error) {
+	return entry.file.Write(p)
+}
+
+// Commit makes the entry visible in the cache.
+func (entry *Entry) Commit() error {
+	if entry.done {
+		return nil
+	}
+	entry.done = true
+
+	if err := entry.file.Close(); err != nil {
+		return Error.Wrap(errs.Combine(err, os.Remove(entry.file.Name())))
+	}
+	if err := os.Rename(entry.file.Name(), filepath.Join(entry.cache.dir, entry.key)); err != nil {
+		return Error.Wrap(errs.Combine(err, os.Remove(entry.file.Name())))
+	}
+	return entry.cache.evict()
+}
+
+// Abort discards the entry.
+func (entry *Entry) Abort() error {
+	if entry.done {
+		return nil
+	}
+	entry.done = true
+
+	return Error.Wrap(errs.Combine(entry.file.Close(), os.Remove(entry.file.Name())))
+}
+
+func ignoreNotExist(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
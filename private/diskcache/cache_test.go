@@ -0,0 +1,106 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package diskcache_test
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink/private/diskcache"
+)
+
+func TestCache(t *testing.T) {
+	cache, err := diskcache.New(t.TempDir(), 10, 0)
+	require.NoError(t, err)
+
+	key := diskcache.Key("bucket", "key", "version")
+	require.NotEqual(t, key, diskcache.Key("bucket", "keyversion"))
+
+	file, err := cache.Open(key)
+	require.NoError(t, err)
+	require.Nil(t, file)
+
+	// aborted entries are not visible
+	entry, err := cache.Create(key)
+	require.NoError(t, err)
+	_, err = entry.Write([]byte("aborted"))
+	require.NoError(t, err)
+	require.NoError(t, entry.Abort())
+
+	file, err = cache.Open(key)
+	require.NoError(t, err)
+	require.Nil(t, file)
+
+	write := func(key, data string) {
+		entry, err := cache.Create(key)
+		require.NoError(t, err)
+		_, err = entry.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, entry.Commit())
+	}
+	read := func(key string) *string {
+		file, err := cache.Open(key)
+		require.NoError(t, err)
+		if file == nil {
+			return nil
+		}
+		defer func() { require.NoError(t, file.Close()) }()
+		data, err := io.ReadAll(file)
+		require.NoError(t, err)
+		s := string(data)
+		return &s
+	}
+
+	write(key, "hello")
+	require.Equal(t, "hello", *read(key))
+
+	// the oldest entry is evicted when the cache is full
+	past := time.Now().Add(-time.Hour)
+	other := diskcache.Key("other")
+	write(other, "world")
+	require.NotNil(t, read(key))
+	require.NotNil(t, read(other))
+
+	require.NoError(t, os.Chtimes(cacheFile(t, cache, key), past, past))
+	write(diskcache.Key("third"), "!")
+	require.Nil(t, read(key))
+	require.Equal(t, "world", *read(other))
+	require.Equal(t, "!", *read(diskcache.Key("third")))
+}
+
+func TestCache_TTL(t *testing.T) {
+	cache, err := diskcache.New(t.TempDir(), 0, time.Minute)
+	require.NoError(t, err)
+
+	key := diskcache.Key("key")
+	entry, err := cache.Create(key)
+	require.NoError(t, err)
+	_, err = entry.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, entry.Commit())
+
+	file, err := cache.Open(key)
+	require.NoError(t, err)
+	require.NotNil(t, file)
+	require.NoError(t, file.Close())
+
+	past := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(cacheFile(t, cache, key), past, past))
+
+	file, err = cache.Open(key)
+	require.NoError(t, err)
+	require.Nil(t, file)
+}
+
+func cacheFile(t *testing.T, cache *diskcache.Cache, key string) string {
+	file, err := cache.Open(key)
+	require.NoError(t, err)
+	require.NotNil(t, file)
+	require.NoError(t, file.Close())
+	return file.Name()
+}
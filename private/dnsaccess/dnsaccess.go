@@ -0,0 +1,98 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package dnsaccess reads access grants from DNS TXT records, in the same
+// format as the records used for hosting with linksharing on custom domains.
+//
+// The records are looked up on the txt-<host> subdomain. The access grant
+// is either a single "storj-access:<grant>" record, or is split into numbered
+// "storj-grant-NN:<part>" records, which are joined in order.
+package dnsaccess
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the error class for this package.
+var Error = errs.Class("dnsaccess")
+
+// accessKeyIDLength is the length of access key IDs registered with the
+// auth service, which can be used in storj-access records too.
+const accessKeyIDLength = 28
+
+// Resolver looks up TXT records.
+type Resolver interface {
+	LookupTXT(ctx context.Context, host string) ([]string, error)
+}
+
+// Lookup returns the serialized access grant stored in the TXT records of
+// host.
+func Lookup(ctx context.Context, resolver Resolver, host string) (string, error) {
+	host = strings.TrimSuffix(host, ".")
+	if host == "" {
+		return "", Error.New("host is empty")
+	}
+
+	records, err := resolver.LookupTXT(ctx, "txt-"+host)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+
+	return Parse(records)
+}
+
+// Parse returns the serialized access grant from TXT records.
+func Parse(records []string) (string, error) {
+	var access string
+	type part struct {
+		index int
+		value string
+	}
+	var parts []part
+
+	for _, record := range records {
+		key, value, ok := strings.Cut(record, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "storj-access":
+			access = value
+		case strings.HasPrefix(key, "storj-grant-"):
+			index, err := strconv.Atoi(strings.TrimPrefix(key, "storj-grant-"))
+			if err != nil {
+				return "", Error.New("invalid record %q", key)
+			}
+			parts = append(parts, part{index: index, value: value})
+		}
+	}
+
+	switch {
+	case access != "" && len(parts) > 0:
+		return "", Error.New("both storj-access and storj-grant records are present")
+	case len(parts) > 0:
+		sort.Slice(parts, func(i, k int) bool { return parts[i].index < parts[k].index })
+		var b strings.Builder
+		for i, part := range parts {
+			if i > 0 && part.index == parts[i-1].index {
+				return "", Error.New("duplicate storj-grant-%02d record", part.index)
+			}
+			b.WriteString(part.value)
+		}
+		return b.String(), nil
+	case len(access) == accessKeyIDLength:
+		return "", Error.New("access key IDs need the auth service and are not supported")
+	case access != "":
+		return access, nil
+	default:
+		return "", Error.New("no storj-access or storj-grant records")
+	}
+}
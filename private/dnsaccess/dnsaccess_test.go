@@ -0,0 +1,51 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package dnsaccess_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink/private/dnsaccess"
+)
+
+type fakeResolver map[string][]string
+
+func (resolver fakeResolver) LookupTXT(ctx context.Context, host string) ([]string, error) {
+	return resolver[host], nil
+}
+
+func TestLookup(t *testing.T) {
+	resolver := fakeResolver{
+		"txt-single.example.com": {"storj-root:bucket/prefix", "storj-access:grant"},
+		"txt-split.example.com":  {"storj-grant-02:c", "storj-root:bucket", "storj-grant-00:a", "storj-grant-01:b"},
+	}
+
+	access, err := dnsaccess.Lookup(context.Background(), resolver, "single.example.com")
+	require.NoError(t, err)
+	require.Equal(t, "grant", access)
+
+	access, err = dnsaccess.Lookup(context.Background(), resolver, "split.example.com.")
+	require.NoError(t, err)
+	require.Equal(t, "abc", access)
+
+	_, err = dnsaccess.Lookup(context.Background(), resolver, "missing.example.com")
+	require.Error(t, err)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, records := range [][]string{
+		nil,
+		{"storj-root:bucket"},
+		{"storj-access:jwaohtj3dhixxfpzhwj522x7z3pb"},
+		{"storj-access:grant", "storj-grant-00:a"},
+		{"storj-grant-00:a", "storj-grant-00:b"},
+		{"storj-grant-x:a"},
+	} {
+		_, err := dnsaccess.Parse(records)
+		require.Error(t, err, records)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package durability estimates the durability of segments for a given
+// Reed-Solomon scheme, node churn and repair latency.
+//
+// Every piece of a segment is assumed to be lost independently, with an
+// exponentially distributed lifetime derived from the daily churn. Once a
+// segment has RepairThreshold or fewer pieces left, it is repaired back to
+// OptimalShares after RepairLatency. A segment is lost when it has fewer than
+// RequiredShares pieces left.
+package durability
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the error class for this package.
+var Error = errs.Class("durability")
+
+const day = 24 * time.Hour
+
+// Params describes the redundancy scheme and the environment of segments.
+type Params struct {
+	// RequiredShares is the number of pieces needed to reconstruct a segment.
+	RequiredShares int
+	// RepairThreshold is the number of pieces at or below which a segment is
+	// repaired.
+	RepairThreshold int
+	// OptimalShares is the number of pieces a segment has after upload and
+	// after repair.
+	OptimalShares int
+
+	// DailyChurn is the fraction of nodes that leave the network per day.
+	DailyChurn float64
+	// RepairLatency is the time between a segment reaching the repair
+	// threshold and being repaired.
+	RepairLatency time.Duration
+}
+
+// Validate checks whether the parameters are consistent.
+func (params Params) Validate() error {
+	switch {
+	case params.RequiredShares <= 0:
+		return Error.New("required shares must be positive, got %d", params.RequiredShares)
+	case params.RepairThreshold < params.RequiredShares:
+		return Error.New("repair threshold %d must not be less than required shares %d", params.RepairThreshold, params.RequiredShares)
+	case params.OptimalShares <= params.RepairThreshold:
+		return Error.New("optimal shares %d must be greater than repair threshold %d", params.OptimalShares, params.RepairThreshold)
+	case params.DailyChurn < 0 || params.DailyChurn >= 1:
+		return Error.New("daily churn must be in [0, 1), got %v", params.DailyChurn)
+	case params.RepairLatency < 0:
+		return Error.New("repair latency must not be negative, got %v", params.RepairLatency)
+	}
+	return nil
+}
+
+// lossRate returns the rate per nanosecond at which a single piece is lost.
+func (params Params) lossRate() float64 {
+	if params.DailyChurn == 0 {
+		return 0
+	}
+	return -math.Log(1-params.DailyChurn) / float64(day)
+}
+
+// pieceLossProbability returns the probability that a single piece is lost
+// within d.
+func (params Params) pieceLossProbability(d time.Duration) float64 {
+	return 1 - math.Exp(-params.lossRate()*float64(d))
+}
+
+// RepairWindowLoss returns the probability that a segment that has just
+// reached the repair threshold is lost before it is repaired.
+func RepairWindowLoss(params Params) (float64, error) {
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+
+	// the segment is lost when more than RepairThreshold-RequiredShares of
+	// the remaining RepairThreshold pieces are lost during the latency.
+	n := params.RepairThreshold
+	p := params.pieceLossProbability(params.RepairLatency)
+	tolerated := params.RepairThreshold - params.RequiredShares
+
+	var survive float64
+	for lost := 0; lost <= tolerated; lost++ {
+		survive += binomial(n, lost, p)
+	}
+	return clamp(1 - survive), nil
+}
+
+// binomial returns the probability of exactly k successes in n trials with
+// success probability p.
+func binomial(n, k int, p float64) float64 {
+	if p == 0 {
+		if k == 0 {
+			return 1
+		}
+		return 0
+	}
+	if p == 1 {
+		if k == n {
+			return 1
+		}
+		return 0
+	}
+	lgammaN, _ := math.Lgamma(float64(n + 1))
+	lgammaK, _ := math.Lgamma(float64(k + 1))
+	lgammaNK, _ := math.Lgamma(float64(n - k + 1))
+	logCoefficient := lgammaN - lgammaK - lgammaNK
+	return math.Exp(logCoefficient + float64(k)*math.Log(p) + float64(n-k)*math.Log1p(-p))
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	// Segments is the number of simulated segments.
+	Segments int
+	// Lost is the number of segments that were lost.
+	Lost int
+	// Repairs is the number of repairs of all segments.
+	Repairs int
+}
+
+// LossProbability returns the fraction of segments that were lost.
+func (result Result) LossProbability() float64 {
+	if result.Segments == 0 {
+		return 0
+	}
+	return float64(result.Lost) / float64(result.Segments)
+}
+
+// RepairsPerSegment returns the average number of repairs per segment.
+func (result Result) RepairsPerSegment() float64 {
+	if result.Segments == 0 {
+		return 0
+	}
+	return float64(result.Repairs) / float64(result.Segments)
+}
+
+// Simulate simulates the given number of segments over duration, starting
+// with OptimalShares pieces each. rng is used for all random decisions, so
+// simulations with the same seed are reproducible.
+func Simulate(params Params, duration time.Duration, segments int, rng *rand.Rand) (Result, error) {
+	if err := params.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Segments: segments}
+	rate := params.lossRate()
+	if rate == 0 {
+		return result, nil
+	}
+
+	for i := 0; i < segments; i++ {
+		lost, repairs := simulateSegment(params, rate, float64(duration), rng)
+		result.Repairs += repairs
+		if lost {
+			result.Lost++
+		}
+	}
+	return result, nil
+}
+
+// simulateSegment simulates a single segment. Piece lifetimes are
+// exponential, so the time to the next piece loss only depends on the number
+// of pieces.
+func simulateSegment(params Params, rate, duration float64, rng *rand.Rand) (lost bool, repairs int) {
+	pieces := params.OptimalShares
+	now := 0.0
+	repairAt := math.Inf(1)
+
+	for {
+		nextLoss := now + rng.ExpFloat64()/(rate*float64(pieces))
+
+		if repairAt <= nextLoss {
+			if repairAt >= duration {
+				return false, repairs
+			}
+			now = repairAt
+			pieces = params.OptimalShares
+			repairAt = math.Inf(1)
+			repairs++
+			continue
+		}
+
+		if nextLoss >= duration {
+			return false, repairs
+		}
+
+		now = nextLoss
+		pieces--
+		if pieces < params.RequiredShares {
+			return true, repairs
+		}
+		if pieces <= params.RepairThreshold && math.IsInf(repairAt, 1) {
+			repairAt = now + float64(params.RepairLatency)
+		}
+	}
+}
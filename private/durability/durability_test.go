@@ -0,0 +1,98 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package durability_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink/private/durability"
+)
+
+var defaultParams = durability.Params{
+	RequiredShares:  29,
+	RepairThreshold: 35,
+	OptimalShares:   65,
+	DailyChurn:      0.01,
+	RepairLatency:   24 * time.Hour,
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, defaultParams.Validate())
+
+	for _, modify := range []func(*durability.Params){
+		func(p *durability.Params) { p.RequiredShares = 0 },
+		func(p *durability.Params) { p.RepairThreshold = p.RequiredShares - 1 },
+		func(p *durability.Params) { p.OptimalShares = p.RepairThreshold },
+		func(p *durability.Params) { p.DailyChurn = -0.1 },
+		func(p *durability.Params) { p.DailyChurn = 1 },
+		func(p *durability.Params) { p.RepairLatency = -time.Second },
+	} {
+		params := defaultParams
+		modify(&params)
+		require.Error(t, params.Validate())
+
+		_, err := durability.RepairWindowLoss(params)
+		require.Error(t, err)
+		_, err = durability.Simulate(params, time.Hour, 1, rand.New(rand.NewSource(0)))
+		require.Error(t, err)
+	}
+}
+
+func TestRepairWindowLoss(t *testing.T) {
+	params := defaultParams
+	params.DailyChurn = 0
+	loss, err := durability.RepairWindowLoss(params)
+	require.NoError(t, err)
+	require.Zero(t, loss)
+
+	params = defaultParams
+	loss, err = durability.RepairWindowLoss(params)
+	require.NoError(t, err)
+	require.Greater(t, loss, 0.0)
+	require.Less(t, loss, 1e-6)
+
+	// slower repair and higher churn are riskier
+	params.RepairLatency = 30 * 24 * time.Hour
+	slower, err := durability.RepairWindowLoss(params)
+	require.NoError(t, err)
+	require.Greater(t, slower, loss)
+
+	params.DailyChurn = 0.5
+	churny, err := durability.RepairWindowLoss(params)
+	require.NoError(t, err)
+	require.Greater(t, churny, slower)
+	require.InDelta(t, 1.0, churny, 1e-6)
+}
+
+func TestSimulate(t *testing.T) {
+	rng := func() *rand.Rand { return rand.New(rand.NewSource(1)) }
+
+	params := defaultParams
+	params.DailyChurn = 0
+	result, err := durability.Simulate(params, 365*24*time.Hour, 100, rng())
+	require.NoError(t, err)
+	require.Equal(t, durability.Result{Segments: 100}, result)
+
+	params = defaultParams
+	result, err = durability.Simulate(params, 365*24*time.Hour, 100, rng())
+	require.NoError(t, err)
+	require.Zero(t, result.Lost)
+	require.Greater(t, result.RepairsPerSegment(), 1.0)
+
+	// simulations are reproducible
+	again, err := durability.Simulate(params, 365*24*time.Hour, 100, rng())
+	require.NoError(t, err)
+	require.Equal(t, result, again)
+
+	// without timely repair segments get lost
+	params.DailyChurn = 0.2
+	params.RepairLatency = 30 * 24 * time.Hour
+	result, err = durability.Simulate(params, 365*24*time.Hour, 100, rng())
+	require.NoError(t, err)
+	require.Equal(t, 1.0, result.LossProbability())
+}
@@ -24,6 +24,7 @@ import (
 	"storj.io/eventkit"
 	"storj.io/uplink/private/eestream"
 	"storj.io/uplink/private/piecestore"
+	"storj.io/uplink/private/stall"
 )
 
 var mon = monkit.Package()
@@ -40,6 +41,7 @@ type Client interface {
 	Get(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, size int64) (ranger.Ranger, error)
 	GetWithOptions(ctx context.Context, limits []*pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, es eestream.ErasureScheme, size int64, opts GetOptions) (ranger.Ranger, error)
 	WithForceErrorDetection(force bool) Client
+	WithLongTail(longTail LongTail) Client
 	// PutPiece is not intended to be used by normal uplinks directly, but is exported to support storagenode graceful exit transfers.
 	PutPiece(ctx, parent context.Context, limit *pb.AddressedOrderLimit, privateKey storj.PiecePrivateKey, data io.ReadCloser) (hash *pb.PieceHash, id *struct{}, err error)
 }
@@ -50,6 +52,7 @@ type ecClient struct {
 	dialer              rpc.Dialer
 	memoryLimit         int
 	forceErrorDetection bool
+	longTail            LongTail
 }
 
 // New creates a client from the given dialer and max buffer memory.
@@ -65,6 +68,11 @@ func (ec *ecClient) WithForceErrorDetection(force bool) Client {
 	return ec
 }
 
+func (ec *ecClient) WithLongTail(longTail LongTail) Client {
+	ec.longTail = longTail
+	return ec
+}
+
 func (ec *ecClient) dialPiecestore(ctx context.Context, n storj.NodeURL) (*piecestore.Client, error) {
 	hashAlgo := piecestore.GetPieceHashAlgo(ctx)
 	client, err := piecestore.DialReplaySafe(ctx, ec.dialer, n, piecestore.DefaultConfig)
@@ -146,6 +154,8 @@ func (ec *ecClient) put(ctx context.Context, limits []*pb.AddressedOrderLimit, p
 		}(i, addressedLimit)
 	}
 
+	successThreshold := ec.longTail.successThreshold(rs, nonNilLimits)
+
 	successfulNodes = make([]*pb.Node, pieceCount)
 	successfulHashes = make([]*pb.PieceHash, pieceCount)
 	var successfulCount, failureCount, cancellationCount int32
@@ -176,7 +186,7 @@ func (ec *ecClient) put(ctx context.Context, limits []*pb.AddressedOrderLimit, p
 		successfulHashes[info.i] = info.hash
 
 		successfulCount++
-		if int(successfulCount) >= rs.OptimalThreshold() {
+		if int(successfulCount) >= successThreshold {
 			// cancelling remaining uploads
 			piecesCancel()
 		}
@@ -219,6 +229,14 @@ func (ec *ecClient) PutPiece(ctx, parent context.Context, limit *pb.AddressedOrd
 
 	storageNodeID := limit.GetLimit().StorageNodeId
 	defer mon.Task()(&ctx, "node: "+storageNodeID.String()[0:8])(&err)
+
+	ctx, cancel := withPieceDeadline(ctx)
+	defer cancel()
+
+	if detector := stall.FromContext(ctx); detector != nil {
+		detector.Started(storageNodeID)
+		defer func() { detector.Finished(storageNodeID, err == nil) }()
+	}
 	start := time.Now()
 	measuredReader := countingReader{R: data}
 	defer func() {
@@ -268,6 +286,8 @@ func (ec *ecClient) PutPiece(ctx, parent context.Context, limit *pb.AddressedOrd
 			// make sure context.Canceled is the primary error in the error chain
 			// for later errors.Is/errs2.IsCanceled checking
 			err = errs.Combine(context.Canceled, err)
+		} else if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = Error.New("upload timed out after %v (node:%v): %w", time.Since(start), storageNodeID, err)
 		} else {
 			nodeAddress := ""
 			if limit.GetStorageNodeAddress() != nil {
@@ -325,6 +345,8 @@ func (ec *ecClient) GetWithOptions(ctx context.Context, limits []*pb.AddressedOr
 		}
 	}
 
+	rrs = gateRangers(rrs, ec.longTail.preferredLimits(limits, es))
+
 	rr, err = eestream.Decode(rrs, es, ec.memoryLimit, opts.ErrorDetection || ec.forceErrorDetection)
 	if err != nil {
 		return nil, Error.Wrap(err)
@@ -381,7 +403,7 @@ func (lr *lazyPieceRanger) Size() int64 {
 func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	ctx, cancel := context.WithCancel(ctx)
+	ctx, cancel := withPieceDeadline(ctx)
 
 	return &lazyPieceReader{
 		ranger: lr,
@@ -407,9 +429,18 @@ type lazyPieceReader struct {
 
 func (lr *lazyPieceReader) Read(data []byte) (_ int, err error) {
 	if err := lr.dial(); err != nil {
-		return 0, err
+		return 0, lr.wrapTimeout(err)
+	}
+	n, err := lr.download.Read(data)
+	return n, lr.wrapTimeout(err)
+}
+
+// wrapTimeout identifies the node in err when the piece deadline passed.
+func (lr *lazyPieceReader) wrapTimeout(err error) error {
+	if err == nil || errors.Is(err, io.EOF) || !errors.Is(lr.ctx.Err(), context.DeadlineExceeded) {
+		return err
 	}
-	return lr.download.Read(data)
+	return Error.New("download timed out (node:%v): %w", lr.ranger.limit.GetLimit().StorageNodeId, err)
 }
 
 func (lr *lazyPieceReader) dial() error {
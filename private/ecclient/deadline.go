@@ -0,0 +1,36 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"time"
+)
+
+// pieceDeadlineShare is the share of the time left until the deadline of the
+// caller that a piece transfer may use. The rest is reserved for the other
+// transfers of the operation and for reporting which transfers failed,
+// before the caller's deadline passes.
+const pieceDeadlineShare = 0.9
+
+// withPieceDeadline returns a context for a piece transfer. When ctx has a
+// deadline, the transfer gets an earlier one, so it fails with an error
+// identifying the node instead of being killed by the caller's deadline.
+func withPieceDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, pieceDeadline(time.Now(), deadline))
+}
+
+// pieceDeadline returns the deadline of a piece transfer started at now for an
+// operation that must finish by deadline.
+func pieceDeadline(now, deadline time.Time) time.Time {
+	remaining := deadline.Sub(now)
+	if remaining <= 0 {
+		return deadline
+	}
+	return now.Add(time.Duration(float64(remaining) * pieceDeadlineShare))
+}
@@ -0,0 +1,38 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPieceDeadline(t *testing.T) {
+	now := time.Now()
+
+	require.Equal(t, now.Add(9*time.Second), pieceDeadline(now, now.Add(10*time.Second)))
+	require.Equal(t, now.Add(-time.Second), pieceDeadline(now, now.Add(-time.Second)))
+}
+
+func TestWithPieceDeadline(t *testing.T) {
+	ctx, cancel := withPieceDeadline(context.Background())
+	_, ok := ctx.Deadline()
+	require.False(t, ok)
+	cancel()
+	require.Error(t, ctx.Err())
+
+	deadline := time.Now().Add(time.Hour)
+	parent, parentCancel := context.WithDeadline(context.Background(), deadline)
+	defer parentCancel()
+
+	ctx, cancel = withPieceDeadline(parent)
+	defer cancel()
+	pieceDeadline, ok := ctx.Deadline()
+	require.True(t, ok)
+	require.True(t, pieceDeadline.Before(deadline))
+	require.True(t, pieceDeadline.After(time.Now().Add(50*time.Minute)))
+}
@@ -0,0 +1,191 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+
+	"storj.io/common/pb"
+	"storj.io/common/ranger"
+	"storj.io/uplink/private/eestream"
+)
+
+// LongTail configures when redundant piece transfers are cancelled.
+type LongTail struct {
+	// SuccessThresholdMultiplier scales the optimal threshold of the
+	// redundancy scheme to get the number of successful piece uploads after
+	// which the remaining uploads are cancelled. Values lower than 1 are
+	// treated as 1.
+	SuccessThresholdMultiplier float64
+	// MinimumSuccesses is the minimum number of successful piece uploads
+	// before the remaining uploads are cancelled.
+	MinimumSuccesses int
+	// DownloadExtraPieces is the number of pieces that are downloaded in
+	// addition to the required count. The other pieces are only downloaded
+	// when one of them fails. Zero means all pieces are downloaded at once.
+	DownloadExtraPieces int
+}
+
+// successThreshold returns the number of successful piece uploads after which
+// the remaining uploads are cancelled. It's never lower than the optimal
+// threshold and never higher than the number of limits.
+func (longTail LongTail) successThreshold(rs eestream.RedundancyStrategy, limits int) int {
+	optimal := rs.OptimalThreshold()
+
+	threshold := optimal
+	if longTail.SuccessThresholdMultiplier > 1 {
+		threshold = int(math.Ceil(float64(optimal) * longTail.SuccessThresholdMultiplier))
+	}
+	if longTail.MinimumSuccesses > threshold {
+		threshold = longTail.MinimumSuccesses
+	}
+	if threshold > limits {
+		threshold = limits
+	}
+	if threshold < optimal {
+		threshold = optimal
+	}
+	return threshold
+}
+
+// SuccessThreshold returns the number of successful piece uploads of a segment
+// with limits, after which the remaining uploads are cancelled.
+func (ec *ecClient) SuccessThreshold(rs eestream.RedundancyStrategy, limits int) int {
+	return ec.longTail.successThreshold(rs, limits)
+}
+
+// preferredLimits returns the indexes of the limits, whose pieces are
+// downloaded first: a random selection of DownloadExtraPieces limits beyond
+// the required count, so the load is spread over the storage nodes. The other
+// pieces are only downloaded when one of the preferred ones fails. It returns
+// nil when all pieces should be downloaded at once.
+func (longTail LongTail) preferredLimits(limits []*pb.AddressedOrderLimit, es eestream.ErasureScheme) map[int]bool {
+	if longTail.DownloadExtraPieces <= 0 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(limits))
+	for i, limit := range limits {
+		if limit != nil {
+			indexes = append(indexes, i)
+		}
+	}
+
+	keep := es.RequiredCount() + longTail.DownloadExtraPieces
+	if len(indexes) <= keep {
+		return nil
+	}
+
+	rand.Shuffle(len(indexes), func(i, k int) {
+		indexes[i], indexes[k] = indexes[k], indexes[i]
+	})
+
+	preferred := make(map[int]bool, keep)
+	for _, i := range indexes[:keep] {
+		preferred[i] = true
+	}
+	return preferred
+}
+
+// gateRangers makes the pieces, which aren't preferred, wait until a
+// preferred one fails. All rangers are returned, so the decoder can fall
+// back to any of them.
+func gateRangers(rrs map[int]ranger.Ranger, preferred map[int]bool) map[int]ranger.Ranger {
+	if preferred == nil {
+		return rrs
+	}
+
+	gate := &downloadGate{
+		fallback: make(chan struct{}, len(rrs)),
+	}
+
+	gated := make(map[int]ranger.Ranger, len(rrs))
+	for i, rr := range rrs {
+		gated[i] = &gatedRanger{
+			Ranger: rr,
+			gate:   gate,
+			wait:   !preferred[i],
+		}
+	}
+	return gated
+}
+
+// downloadGate starts a waiting piece download for every failed one.
+type downloadGate struct {
+	fallback chan struct{}
+}
+
+// release lets a waiting piece download start.
+func (gate *downloadGate) release() {
+	select {
+	case gate.fallback <- struct{}{}:
+	default:
+	}
+}
+
+// gatedRanger delays reading a piece until the gate releases it, when wait is
+// set, and releases another piece when reading fails.
+type gatedRanger struct {
+	ranger.Ranger
+	gate *downloadGate
+	wait bool
+}
+
+// Range implements ranger.Ranger.
+func (rr *gatedRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	reader, err := rr.Ranger.Range(ctx, offset, length)
+	if err != nil {
+		rr.gate.release()
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	return &gatedReader{
+		ReadCloser: reader,
+		ctx:        ctx,
+		cancel:     cancel,
+		gate:       rr.gate,
+		waiting:    rr.wait,
+	}, nil
+}
+
+// gatedReader is returned by gatedRanger.
+type gatedReader struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel func()
+	gate   *downloadGate
+
+	waiting bool
+	failed  bool
+}
+
+// Read implements io.Reader.
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if r.waiting {
+		select {
+		case <-r.gate.fallback:
+			r.waiting = false
+		case <-r.ctx.Done():
+			return 0, r.ctx.Err()
+		}
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) && !r.failed {
+		r.failed = true
+		r.gate.release()
+	}
+	return n, err
+}
+
+// Close implements io.Closer. It stops waiting for the gate.
+func (r *gatedReader) Close() error {
+	r.cancel()
+	return r.ReadCloser.Close()
+}
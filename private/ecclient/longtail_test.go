@@ -0,0 +1,207 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/ranger"
+	"storj.io/common/rpc"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/infectious"
+	"storj.io/uplink/private/eestream"
+	"storj.io/uplink/private/storage/streams/pieceupload"
+	"storj.io/uplink/private/storage/streams/segmentupload"
+)
+
+func TestLongTail_SuccessThreshold(t *testing.T) {
+	rs, err := eestream.NewRedundancyStrategyFromStorj(storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 2,
+		RepairShares:   3,
+		OptimalShares:  4,
+		TotalShares:    8,
+	})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		longTail LongTail
+		limits   int
+		expected int
+	}{
+		{LongTail{}, 8, 4},
+		{LongTail{SuccessThresholdMultiplier: 0.5}, 8, 4},
+		{LongTail{SuccessThresholdMultiplier: 1.5}, 8, 6},
+		{LongTail{SuccessThresholdMultiplier: 1.1}, 8, 5},
+		{LongTail{SuccessThresholdMultiplier: 3}, 8, 8},
+		{LongTail{SuccessThresholdMultiplier: 1.5}, 5, 5},
+		{LongTail{MinimumSuccesses: 2}, 8, 4},
+		{LongTail{MinimumSuccesses: 7}, 8, 7},
+		{LongTail{MinimumSuccesses: 7, SuccessThresholdMultiplier: 1.5}, 8, 7},
+		{LongTail{MinimumSuccesses: 7}, 3, 4},
+	} {
+		require.Equal(t, tt.expected, tt.longTail.successThreshold(rs, tt.limits), "%+v %d", tt.longTail, tt.limits)
+	}
+}
+
+func TestLongTail_PreferredLimits(t *testing.T) {
+	rs, err := eestream.NewRedundancyStrategyFromStorj(storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 2,
+		RepairShares:   3,
+		OptimalShares:  4,
+		TotalShares:    6,
+	})
+	require.NoError(t, err)
+
+	limit := &pb.AddressedOrderLimit{}
+	limits := []*pb.AddressedOrderLimit{limit, nil, limit, limit, nil, limit}
+
+	require.Nil(t, LongTail{}.preferredLimits(limits, rs))
+	require.Nil(t, LongTail{DownloadExtraPieces: 2}.preferredLimits(limits, rs))
+
+	chosen := map[int]bool{}
+	for i := 0; i < 100; i++ {
+		preferred := LongTail{DownloadExtraPieces: 1}.preferredLimits(limits, rs)
+		require.Len(t, preferred, 3)
+		for index := range preferred {
+			require.NotNil(t, limits[index])
+			chosen[index] = true
+		}
+	}
+	// the preferred limits are shuffled, so every limit gets chosen.
+	require.Len(t, chosen, 4)
+}
+
+func TestLongTail_DownloadFallback(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	fc, err := infectious.NewFEC(2, 4)
+	require.NoError(t, err)
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, 1024), 0, 0)
+	require.NoError(t, err)
+
+	data := testrand.Bytes(32 * memory.KiB)
+	readers, err := eestream.EncodeReader2(ctx, bytes.NewReader(data), rs)
+	require.NoError(t, err)
+
+	pieces := make([][]byte, len(readers))
+	var group errgroup.Group
+	for i := range readers {
+		i := i
+		group.Go(func() (err error) {
+			defer func() { err = errs.Combine(err, readers[i].Close()) }()
+			pieces[i], err = io.ReadAll(readers[i])
+			return err
+		})
+	}
+	require.NoError(t, group.Wait())
+
+	download := func(rrs map[int]ranger.Ranger) []byte {
+		rr, err := eestream.Decode(gateRangers(rrs, map[int]bool{0: true, 1: true}), rs, 0, false)
+		require.NoError(t, err)
+		reader, err := rr.Range(ctx, 0, rr.Size())
+		require.NoError(t, err)
+		defer ctx.Check(reader.Close)
+		downloaded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		return downloaded
+	}
+
+	t.Run("preferred pieces succeed", func(t *testing.T) {
+		fallback := &countingRanger{Ranger: ranger.ByteRanger(pieces[2])}
+		require.Equal(t, data, download(map[int]ranger.Ranger{
+			0: ranger.ByteRanger(pieces[0]),
+			1: ranger.ByteRanger(pieces[1]),
+			2: fallback,
+		}))
+		require.Zero(t, fallback.reads.Load())
+	})
+
+	t.Run("preferred piece fails", func(t *testing.T) {
+		require.Equal(t, data, download(map[int]ranger.Ranger{
+			0: failingRanger{size: int64(len(pieces[0]))},
+			1: ranger.ByteRanger(pieces[1]),
+			2: ranger.ByteRanger(pieces[2]),
+			3: ranger.ByteRanger(pieces[3]),
+		}))
+	})
+
+	t.Run("preferred pieces fail", func(t *testing.T) {
+		require.Equal(t, data, download(map[int]ranger.Ranger{
+			0: failingRanger{size: int64(len(pieces[0]))},
+			1: failingRanger{size: int64(len(pieces[1]))},
+			2: ranger.ByteRanger(pieces[2]),
+			3: ranger.ByteRanger(pieces[3]),
+		}))
+	})
+}
+
+// countingRanger counts the reads from its ranges.
+type countingRanger struct {
+	ranger.Ranger
+	reads atomic.Int64
+}
+
+func (rr *countingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	reader, err := rr.Ranger.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &readObserver{ReadCloser: reader, reads: &rr.reads}, nil
+}
+
+type readObserver struct {
+	io.ReadCloser
+	reads *atomic.Int64
+}
+
+func (r *readObserver) Read(p []byte) (int, error) {
+	r.reads.Add(1)
+	return r.ReadCloser.Read(p)
+}
+
+// failingRanger returns ranges that fail on the first read.
+type failingRanger struct {
+	size int64
+}
+
+func (rr failingRanger) Size() int64 { return rr.size }
+
+func (rr failingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return io.NopCloser(iotest.ErrReader(errs.New("piece download failed"))), nil
+}
+
+func TestLongTail_SegmentUpload(t *testing.T) {
+	rs, err := eestream.NewRedundancyStrategyFromStorj(storj.RedundancyScheme{
+		Algorithm:      storj.ReedSolomon,
+		ShareSize:      256,
+		RequiredShares: 2,
+		RepairShares:   3,
+		OptimalShares:  4,
+		TotalShares:    8,
+	})
+	require.NoError(t, err)
+
+	// uploads with concurrent segment uploads use the client as piece putter.
+	var putter pieceupload.PiecePutter = New(rpc.Dialer{}, 0).WithLongTail(LongTail{MinimumSuccesses: 6})
+	thresholder, ok := putter.(segmentupload.SuccessThresholder)
+	require.True(t, ok)
+	require.Equal(t, 6, thresholder.SuccessThreshold(rs, 8))
+}
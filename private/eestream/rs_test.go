@@ -219,6 +219,30 @@ func TestRSErrors(t *testing.T) {
 	}
 }
 
+func TestRSErrorsKeepPieceErrors(t *testing.T) {
+	ctx := context.Background()
+
+	fc, err := infectious.NewFEC(2, 4)
+	require.NoError(t, err)
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, 1024), 0, 0)
+	require.NoError(t, err)
+
+	pieceErr := errors.New("piece error")
+	readerMap := make(map[int]io.ReadCloser, 4)
+	for i := 0; i < 4; i++ {
+		readerMap[i] = readcloser.FatalReadCloser(pieceErr)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	decoder := eestream.DecodeReaders2(ctx, cancel, readerMap, rs, 4*1024, 3*1024, false)
+	defer func() { assert.NoError(t, decoder.Close()) }()
+
+	_, err = io.ReadAll(decoder)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, pieceErr))
+	require.True(t, errs.IsFunc(err, func(err error) bool { return err == pieceErr })) //nolint:errorlint // comparing the exact error
+}
+
 // Some pieces will read EOF at the beginning (byte 0).
 // Test will pass if those pieces are less than required.
 func TestRSEOF(t *testing.T) {
@@ -0,0 +1,56 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package scheduler
+
+import (
+	"context"
+)
+
+// Chain is a scheduler that acquires handles and resources from all of its
+// schedulers, so the limits of every one of them apply. Handles and
+// resources are acquired in the order of the schedulers.
+type Chain []*Scheduler
+
+// Join acquires a new Handle from every scheduler in the chain.
+func (c Chain) Join(ctx context.Context) (Handle, bool) {
+	handles := make(chainHandle, 0, len(c))
+	for _, s := range c {
+		h, ok := s.Join(ctx)
+		if !ok {
+			handles.Done()
+			return nil, false
+		}
+		handles = append(handles, h)
+	}
+	return handles, true
+}
+
+type chainHandle []Handle
+
+func (hs chainHandle) Done() {
+	for i := len(hs) - 1; i >= 0; i-- {
+		hs[i].Done()
+	}
+}
+
+func (hs chainHandle) Get(ctx context.Context) (Resource, bool) {
+	resources := make(chainResource, 0, len(hs))
+	for _, h := range hs {
+		r, ok := h.Get(ctx)
+		if !ok {
+			resources.Done()
+			return nil, false
+		}
+		resources = append(resources, r)
+	}
+	return resources, true
+}
+
+type chainResource []Resource
+
+func (rs chainResource) Done() {
+	for i := len(rs) - 1; i >= 0; i-- {
+		rs[i].Done()
+	}
+}
@@ -243,3 +243,45 @@ func BenchmarkScheduler_Parallel(b *testing.B) {
 		}
 	})
 }
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	local := New(Options{MaximumConcurrent: 2})
+	shared := New(Options{MaximumConcurrent: 1, MaximumConcurrentHandles: 1})
+	chain := Chain{local, shared}
+
+	h, ok := chain.Join(ctx)
+	require.True(t, ok)
+
+	r, ok := h.Get(ctx)
+	require.True(t, ok)
+	require.Len(t, local.rsema, 1)
+	require.Len(t, shared.rsema, 1)
+
+	{ // the shared limit applies and the local resource is released
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, ok := h.Get(timeout)
+		require.False(t, ok)
+		require.Len(t, local.rsema, 1)
+	}
+
+	{ // the shared handle limit applies
+		timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, ok := chain.Join(timeout)
+		require.False(t, ok)
+
+		_, ok = chain.Join(canceled)
+		require.False(t, ok)
+	}
+
+	r.Done()
+	h.Done()
+	require.Len(t, local.rsema, 0)
+	require.Len(t, shared.rsema, 0)
+	require.Len(t, shared.hsema, 0)
+}
@@ -207,18 +207,38 @@ func (s *StripeReader) CloseAndWait() error {
 
 func (s *StripeReader) combineErrs() error {
 	var errstrings []string
+	var pieceErrs []error
 	for idx := range s.pieces {
 		if err := s.pieces[idx].buffer.Err(); err != nil && !errors.Is(err, io.EOF) {
 			errstrings = append(errstrings, fmt.Sprintf("\nerror retrieving piece %02d: %v", s.pieces[idx].shareNum, err))
+			pieceErrs = append(pieceErrs, err)
 		}
 	}
 	if len(errstrings) > 0 {
 		sort.Strings(errstrings)
-		return Error.New("failed to download segment: %s", strings.Join(errstrings, ""))
+		return Error.Wrap(&segmentError{
+			message: "failed to download segment: " + strings.Join(errstrings, ""),
+			pieces:  pieceErrs,
+		})
 	}
 	return Error.New("programmer error: no errors to combine")
 }
 
+// segmentError is returned when too many pieces of a segment failed. It
+// keeps the errors of the pieces, so callers can inspect them.
+type segmentError struct {
+	message string
+	pieces  []error
+}
+
+func (err *segmentError) Error() string { return err.message }
+
+// Ungroup returns the errors of the pieces for errs.IsFunc.
+func (err *segmentError) Ungroup() []error { return err.pieces }
+
+// Unwrap returns the errors of the pieces for errors.Is and errors.As.
+func (err *segmentError) Unwrap() []error { return err.pieces }
+
 var backcompatMon = monkit.ScopeNamed("storj.io/storj/uplink/eestream")
 var monReadStripeTask = mon.Task()
 
@@ -19,6 +19,7 @@ import (
 	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/storj"
 	"storj.io/uplink/private/eestream"
+	"storj.io/uplink/private/oteltrace"
 )
 
 var (
@@ -34,6 +35,7 @@ type Client struct {
 	conn      *rpc.Conn
 	client    pb.DRPCMetainfoClient
 	apiKeyRaw []byte
+	timeouts  *timeoutConn
 
 	userAgent string
 }
@@ -64,14 +66,26 @@ func DialNodeURL(ctx context.Context, dialer rpc.Dialer, nodeURL string, apiKey
 		return nil, Error.Wrap(err)
 	}
 
+	timeouts := &timeoutConn{Conn: oteltrace.WrapConn(conn)}
+
 	return &Client{
 		conn:      conn,
-		client:    pb.NewDRPCMetainfoClient(conn),
+		client:    pb.NewDRPCMetainfoClient(timeouts),
 		apiKeyRaw: apiKey.SerializeRaw(),
+		timeouts:  timeouts,
 		userAgent: userAgent,
 	}, nil
 }
 
+// SetTimeout limits the duration of every request made by the client, unless
+// overridden with WithTimeout. Zero or negative timeout means no timeout.
+// It has no effect on clients created with NewClient.
+func (client *Client) SetTimeout(timeout time.Duration) {
+	if client.timeouts != nil {
+		client.timeouts.timeout = timeout
+	}
+}
+
 // Close closes the dialed connection.
 func (client *Client) Close() error {
 	client.mu.Lock()
@@ -830,10 +844,15 @@ func (params *BeginDeleteObjectParams) BatchItem() *pb.BatchRequestItem {
 
 // BeginDeleteObjectResponse response for BeginDeleteObject request.
 type BeginDeleteObjectResponse struct {
+	// Object is the deleted object. It's only returned when the access
+	// allows reading.
+	Object RawObjectItem
 }
 
 func newBeginDeleteObjectResponse(response *pb.ObjectBeginDeleteResponse) BeginDeleteObjectResponse {
-	return BeginDeleteObjectResponse{}
+	return BeginDeleteObjectResponse{
+		Object: newObjectInfo(response.Object),
+	}
 }
 
 // BeginDeleteObject begins object deletion process.
@@ -0,0 +1,88 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metaclient
+
+import (
+	"context"
+
+	"storj.io/common/errs2"
+	"storj.io/common/rpc/rpcstatus"
+)
+
+// Support describes whether the satellite supports a feature.
+type Support int
+
+const (
+	// SupportUnknown means that the probe for the feature didn't tell.
+	SupportUnknown Support = iota
+	// Supported means that the satellite supports the feature.
+	Supported
+	// Unsupported means that the satellite doesn't support the feature.
+	Unsupported
+)
+
+// Capabilities describes the optional features supported by a satellite.
+type Capabilities struct {
+	Versioning     Support
+	ServerSideCopy Support
+	ServerSideMove Support
+	Multipart      Support
+}
+
+// GetCapabilities detects the optional features supported by the satellite.
+//
+// Every feature is probed with a request for an empty bucket name. A
+// satellite that implements the endpoint rejects the request as invalid,
+// while one that doesn't implement it fails with Unimplemented. Any other
+// failure, e.g. a denied permission or an unavailable satellite, leaves the
+// support of the feature unknown.
+func (client *Client) GetCapabilities(ctx context.Context) (capabilities Capabilities, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	capabilities.Versioning = client.probe(ctx, func(ctx context.Context) error {
+		params := GetBucketVersioningParams{}
+		_, err := client.client.GetBucketVersioning(ctx, params.toRequest(client.header()))
+		return err
+	})
+
+	capabilities.ServerSideCopy = client.probe(ctx, func(ctx context.Context) error {
+		params := BeginCopyObjectParams{}
+		_, err := client.client.BeginCopyObject(ctx, params.toRequest(client.header()))
+		return err
+	})
+
+	capabilities.ServerSideMove = client.probe(ctx, func(ctx context.Context) error {
+		params := BeginMoveObjectParams{}
+		_, err := client.client.BeginMoveObject(ctx, params.toRequest(client.header()))
+		return err
+	})
+
+	capabilities.Multipart = client.probe(ctx, func(ctx context.Context) error {
+		params := ListPendingObjectStreamsParams{}
+		_, err := client.client.ListPendingObjectStreams(ctx, params.toRequest(client.header()))
+		return err
+	})
+
+	if err := ctx.Err(); err != nil {
+		return Capabilities{}, Error.Wrap(err)
+	}
+	return capabilities, nil
+}
+
+// probe returns whether the endpoint called by fn is implemented.
+func (client *Client) probe(ctx context.Context, fn func(ctx context.Context) error) Support {
+	err := WithRetry(ctx, fn)
+	switch {
+	case err == nil:
+		return Supported
+	case errs2.IsRPC(err, rpcstatus.Unimplemented):
+		return Unsupported
+	case errs2.IsRPC(err, rpcstatus.InvalidArgument),
+		errs2.IsRPC(err, rpcstatus.NotFound),
+		errs2.IsRPC(err, rpcstatus.FailedPrecondition):
+		return Supported
+	default:
+		return SupportUnknown
+	}
+}
@@ -115,6 +115,17 @@ func IterateBuckets(ctx context.Context, options IterateBucketsOptions) *BucketI
 	return &buckets
 }
 
+// NewStaticBucketIterator returns an iterator over items, which doesn't
+// request anything from the satellite. When err is not nil, the iteration
+// fails with it.
+func NewStaticBucketIterator(items []Bucket, err error) *BucketIterator {
+	return &BucketIterator{
+		list:     &BucketList{Items: items},
+		position: -1,
+		err:      err,
+	}
+}
+
 // BucketIterator is an iterator over a collection of buckets.
 type BucketIterator struct {
 	ctx            context.Context
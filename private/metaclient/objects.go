@@ -222,6 +222,58 @@ func (db *DB) DeleteObject(ctx context.Context, bucket, key string, version []by
 	return db.ObjectFromRawObjectItem(ctx, bucket, key, object)
 }
 
+// DeleteObjects deletes the latest versions of the objects with the specified
+// keys in a single batch request. Keys of objects that don't exist are
+// ignored. The returned found reports for every key whether the satellite
+// returned the deleted object, which it only does when the access allows
+// reading. When the batch fails, none or only some of the objects may have
+// been deleted.
+func (db *DB) DeleteObjects(ctx context.Context, bucket string, keys []string) (found []bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucket == "" {
+		return nil, ErrNoBucket.New("")
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]BatchItem, 0, len(keys))
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, ErrNoPath.New("")
+		}
+
+		encPath, err := encryption.EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted(key), db.encStore)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, &BeginDeleteObjectParams{
+			Bucket:             []byte(bucket),
+			EncryptedObjectKey: []byte(encPath.Raw()),
+		})
+	}
+
+	responses, err := db.metainfo.Batch(ctx, requests...)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != len(keys) {
+		return nil, Error.New("unexpected number of responses: %d, expected %d", len(responses), len(keys))
+	}
+
+	found = make([]bool, len(keys))
+	for i := range responses {
+		response, err := responses[i].BeginDeleteObject()
+		if err != nil {
+			return nil, err
+		}
+		found[i] = response.Object.Bucket != ""
+	}
+	return found, nil
+}
+
 // ModifyPendingObject creates an interface for updating a partially uploaded object.
 func (db *DB) ModifyPendingObject(ctx context.Context, bucket, key string) (object *MutableObject, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -577,6 +629,65 @@ func (db *DB) GetObject(ctx context.Context, bucket, key string, version []byte)
 	return db.ObjectFromRawObjectItem(ctx, bucket, key, objectInfo)
 }
 
+// GetObjectResult is the result of GetObjects for a single key.
+type GetObjectResult struct {
+	Object Object
+	Err    error
+}
+
+// GetObjects returns information about the objects at the specified keys
+// with a single batch request. The satellite fails the whole batch when any
+// of the objects can't be retrieved, e.g. because it doesn't exist, in which
+// case the error of the request is returned. Errors of decrypting the
+// information of a single object are returned in its result.
+func (db *DB) GetObjects(ctx context.Context, bucket string, keys []string) (results []GetObjectResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucket == "" {
+		return nil, ErrNoBucket.New("")
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	requests := make([]BatchItem, 0, len(keys))
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, ErrNoPath.New("")
+		}
+
+		encPath, err := encryption.EncryptPathWithStoreCipher(bucket, paths.NewUnencrypted(key), db.encStore)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, &GetObjectParams{
+			Bucket:                     []byte(bucket),
+			EncryptedObjectKey:         []byte(encPath.Raw()),
+			RedundancySchemePerSegment: true,
+		})
+	}
+
+	responses, err := db.metainfo.Batch(ctx, requests...)
+	if err != nil {
+		return nil, err
+	}
+	if len(responses) != len(keys) {
+		return nil, Error.New("unexpected number of responses: %d, expected %d", len(responses), len(keys))
+	}
+
+	results = make([]GetObjectResult, len(keys))
+	for i, response := range responses {
+		resp, err := response.GetObject()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Object, results[i].Err = db.ObjectFromRawObjectItem(ctx, bucket, keys[i], resp.Info)
+	}
+	return results, nil
+}
+
 // CommitObject commits an object.
 func (db *DB) CommitObject(ctx context.Context, bucket, key, uploadID string, customMetadata map[string]string, encryptionParameters storj.EncryptionParameters) (info Object, err error) {
 	defer mon.Task()(&ctx)(&err)
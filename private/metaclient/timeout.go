@@ -0,0 +1,40 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metaclient
+
+import (
+	"context"
+	"time"
+
+	"storj.io/drpc"
+)
+
+type timeoutKey struct{}
+
+// WithTimeout returns a context that limits every metainfo request made with
+// it to timeout, overriding the timeout of the client. Zero or negative
+// timeout means no timeout.
+func WithTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutKey{}, timeout)
+}
+
+// timeoutConn limits the duration of every unary request.
+type timeoutConn struct {
+	drpc.Conn
+	timeout time.Duration
+}
+
+// Invoke implements drpc.Conn's Invoke method with the request timeout applied.
+func (c *timeoutConn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	timeout := c.timeout
+	if override, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return c.Conn.Invoke(ctx, rpc, enc, in, out)
+}
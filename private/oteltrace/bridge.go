@@ -0,0 +1,156 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package oteltrace bridges monkit spans to OpenTelemetry.
+//
+// The uplink library is instrumented with monkit tasks throughout the
+// upload, download and metainfo paths. Instead of duplicating that
+// instrumentation, this package observes the monkit trace of an operation
+// and mirrors every span into an OpenTelemetry span, so applications get
+// per-segment and per-piece timing as part of their own traces.
+package oteltrace
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name reported to the TracerProvider.
+const instrumentationName = "storj.io/uplink"
+
+type bridgeKey struct{}
+
+// attachMu serializes attaching bridges to monkit traces.
+var attachMu sync.Mutex
+
+// bridge mirrors the spans of a single monkit trace into OpenTelemetry.
+type bridge struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	spans map[int64]trace.Span
+}
+
+// Attach starts mirroring the monkit trace of ctx into OpenTelemetry spans
+// created by provider. The spans that are already running in ctx are
+// included, so the resulting OpenTelemetry trace has the same shape as the
+// monkit one. The OpenTelemetry span in ctx, if any, becomes the parent of
+// the mirrored spans.
+//
+// Attach is a no-op when provider is nil or ctx has no monkit span.
+func Attach(ctx context.Context, provider trace.TracerProvider) {
+	if provider == nil {
+		return
+	}
+
+	current := monkit.SpanFromCtx(ctx)
+	if current == nil {
+		return
+	}
+
+	attachMu.Lock()
+	b, ok := current.Trace().Get(bridgeKey{}).(*bridge)
+	if !ok {
+		b = &bridge{
+			tracer: provider.Tracer(instrumentationName),
+			spans:  map[int64]trace.Span{},
+		}
+		current.Trace().Set(bridgeKey{}, b)
+		current.Trace().ObserveSpansCtx(b)
+	}
+	attachMu.Unlock()
+
+	b.adopt(ctx, current)
+}
+
+// adopt creates spans for current and all of its running ancestors that are
+// not yet mirrored.
+func (b *bridge) adopt(ctx context.Context, current *monkit.Span) {
+	var ancestors []*monkit.Span
+	for s := current; s != nil; s = monkit.SpanFromCtx(s.Context) {
+		ancestors = append(ancestors, s)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		s := ancestors[i]
+		if _, ok := b.spans[s.Id()]; ok {
+			continue
+		}
+		parentCtx := b.parentContext(ctx, s)
+		_, span := b.tracer.Start(parentCtx, s.Func().FullName(), trace.WithTimestamp(s.Start()))
+		b.spans[s.Id()] = span
+	}
+}
+
+// parentContext returns ctx with the mirrored parent span of s, if there is
+// one. b.mu must be held.
+func (b *bridge) parentContext(ctx context.Context, s *monkit.Span) context.Context {
+	if parentID, ok := s.ParentId(); ok {
+		if parent, ok := b.spans[parentID]; ok {
+			return trace.ContextWithSpan(ctx, parent)
+		}
+	}
+	return ctx
+}
+
+// Start implements monkit.SpanCtxObserver.
+func (b *bridge) Start(ctx context.Context, s *monkit.Span) context.Context {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ctx, span := b.tracer.Start(b.parentContext(ctx, s), s.Func().FullName(), trace.WithTimestamp(s.Start()))
+	b.spans[s.Id()] = span
+	return ctx
+}
+
+// Finish implements monkit.SpanCtxObserver.
+func (b *bridge) Finish(ctx context.Context, s *monkit.Span, err error, panicked bool, finish time.Time) {
+	b.mu.Lock()
+	span, ok := b.spans[s.Id()]
+	delete(b.spans, s.Id())
+	b.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	switch {
+	case panicked:
+		span.SetStatus(codes.Error, "panicked")
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End(trace.WithTimestamp(finish))
+}
+
+// spanContext returns the span context of the closest mirrored span of ctx.
+func spanContext(ctx context.Context) trace.SpanContext {
+	current := monkit.SpanFromCtx(ctx)
+	if current == nil {
+		return trace.SpanContextFromContext(ctx)
+	}
+
+	b, ok := current.Trace().Get(bridgeKey{}).(*bridge)
+	if !ok {
+		return trace.SpanContextFromContext(ctx)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := current; s != nil; s = monkit.SpanFromCtx(s.Context) {
+		if span, ok := b.spans[s.Id()]; ok {
+			return span.SpanContext()
+		}
+	}
+	return trace.SpanContextFromContext(ctx)
+}
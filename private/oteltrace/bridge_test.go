@@ -0,0 +1,74 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package oteltrace_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"storj.io/drpc/drpcmetadata"
+	"storj.io/uplink/private/oteltrace"
+)
+
+var mon = monkit.Package()
+
+func TestAttach(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	operation := func(ctx context.Context) (err error) {
+		defer mon.TaskNamed("operation")(&ctx)(&err)
+
+		oteltrace.Attach(ctx, provider)
+		// attaching twice must not duplicate spans
+		oteltrace.Attach(ctx, provider)
+
+		segment := func(ctx context.Context) (err error) {
+			defer mon.TaskNamed("segment")(&ctx)(&err)
+
+			metadata, ok := drpcmetadata.Get(oteltrace.Inject(ctx))
+			require.True(t, ok)
+			require.NotEmpty(t, metadata["traceparent"])
+
+			return errors.New("segment failed")
+		}
+		require.Error(t, segment(ctx))
+
+		return nil
+	}
+	require.NoError(t, operation(context.Background()))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byName[span.Name()] = span
+	}
+
+	operationSpan := byName[mon.FuncNamed("operation").FullName()]
+	segmentSpan := byName[mon.FuncNamed("segment").FullName()]
+	require.NotNil(t, operationSpan)
+	require.NotNil(t, segmentSpan)
+
+	require.Equal(t, operationSpan.SpanContext().TraceID(), segmentSpan.SpanContext().TraceID())
+	require.Equal(t, operationSpan.SpanContext().SpanID(), segmentSpan.Parent().SpanID())
+	require.Len(t, segmentSpan.Events(), 1)
+}
+
+func TestAttach_NilProvider(t *testing.T) {
+	ctx := context.Background()
+	defer mon.Task()(&ctx)(nil)
+
+	oteltrace.Attach(ctx, nil)
+
+	_, ok := drpcmetadata.Get(oteltrace.Inject(ctx))
+	require.False(t, ok)
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package oteltrace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"storj.io/drpc"
+	"storj.io/drpc/drpcmetadata"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Conn wraps a drpc.Conn and injects the W3C trace context of the current
+// mirrored span into the metadata of every request.
+type Conn struct {
+	drpc.Conn
+}
+
+// WrapConn wraps conn so requests carry the trace context.
+func WrapConn(conn drpc.Conn) *Conn {
+	return &Conn{Conn: conn}
+}
+
+// Invoke implements drpc.Conn's Invoke method with trace context injected.
+func (c *Conn) Invoke(ctx context.Context, rpc string, enc drpc.Encoding, in, out drpc.Message) error {
+	return c.Conn.Invoke(Inject(ctx), rpc, enc, in, out)
+}
+
+// NewStream implements drpc.Conn's NewStream method with trace context injected.
+func (c *Conn) NewStream(ctx context.Context, rpc string, enc drpc.Encoding) (drpc.Stream, error) {
+	return c.Conn.NewStream(Inject(ctx), rpc, enc)
+}
+
+// Inject adds the W3C trace context of the closest mirrored span in ctx to
+// the drpc metadata of ctx. It returns ctx unchanged when there is no valid
+// span.
+func Inject(ctx context.Context) context.Context {
+	sc := spanContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return drpcmetadata.AddPairs(ctx, carrier)
+}
@@ -18,6 +18,7 @@ import (
 	"storj.io/common/pb"
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
+	"storj.io/uplink/private/oteltrace"
 )
 
 // NoiseEnabled indicates whether Noise is enabled in this build.
@@ -85,7 +86,7 @@ func Dial(ctx context.Context, dialer rpc.Dialer, nodeURL storj.NodeURL, config
 	}
 
 	return &Client{
-		client:  pb.NewDRPCPiecestoreClient(conn),
+		client:  pb.NewDRPCPiecestoreClient(oteltrace.WrapConn(conn)),
 		nodeURL: nodeURL,
 		conn:    conn,
 		config:  config,
@@ -100,7 +101,7 @@ func DialReplaySafe(ctx context.Context, dialer rpc.Dialer, nodeURL storj.NodeUR
 	}
 
 	return &Client{
-		replaySafe: pb.NewDRPCReplaySafePiecestoreClient(conn),
+		replaySafe: pb.NewDRPCReplaySafePiecestoreClient(oteltrace.WrapConn(conn)),
 		nodeURL:    nodeURL,
 		conn:       conn,
 		config:     config,
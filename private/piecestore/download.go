@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"storj.io/common/context2"
 	"storj.io/common/errs2"
 	"storj.io/common/pb"
+	"storj.io/common/rpc/rpcstatus"
 	"storj.io/common/signing"
 	"storj.io/common/storj"
 	"storj.io/common/sync2"
@@ -265,6 +267,22 @@ func (client *Download) Read(data []byte) (read int, err error) {
 	return read, nil
 }
 
+// IsOrderLimitExpired returns whether err contains the error of a storage
+// node that rejected an order limit because it expired or was created too
+// long ago. Downloads can be retried with new order limits in that case.
+func IsOrderLimitExpired(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		if rpcstatus.Code(err) != rpcstatus.InvalidArgument {
+			return false
+		}
+		// storage nodes return InvalidArgument for every invalid order limit,
+		// so the expired ones can only be told apart by the message.
+		message := err.Error()
+		return strings.Contains(message, "order expired") ||
+			strings.Contains(message, "order created too long ago")
+	})
+}
+
 // handleClosingError should be used for an error that also closed the stream.
 func (client *Download) handleClosingError(err error) {
 	client.close.Do(func() {
@@ -0,0 +1,21 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+)
+
+func TestPieceHashAlgo(t *testing.T) {
+	ctx := context.Background()
+	require.Equal(t, pb.PieceHashAlgorithm_BLAKE3, GetPieceHashAlgo(ctx))
+
+	ctx = WithPieceHashAlgo(ctx, pb.PieceHashAlgorithm_SHA256)
+	require.Equal(t, pb.PieceHashAlgorithm_SHA256, GetPieceHashAlgo(ctx))
+}
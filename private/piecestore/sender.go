@@ -0,0 +1,71 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"storj.io/common/pb"
+)
+
+// sender sends upload requests in a separate goroutine, so that reading,
+// hashing and signing the next chunk on the caller's goroutine overlaps with
+// sending the current one. At most one request is in flight.
+//
+// The stream must not be used otherwise until Wait or Close returned.
+type sender struct {
+	stream   uploadStream
+	requests chan *pb.PieceUploadRequest
+	results  chan error
+	stopped  chan struct{}
+	inflight bool
+}
+
+// newSender starts a sender for stream.
+func newSender(stream uploadStream) *sender {
+	s := &sender{
+		stream:   stream,
+		requests: make(chan *pb.PieceUploadRequest),
+		results:  make(chan error, 1),
+		stopped:  make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// run sends requests until the sender is closed.
+func (s *sender) run() {
+	defer close(s.stopped)
+	for req := range s.requests {
+		s.results <- s.stream.Send(req)
+	}
+}
+
+// Send waits for the request in flight and starts sending req. It returns
+// the error of sending the previous request, in which case req isn't sent.
+// The data of req must not be modified until the next Send, Wait or Close
+// returned.
+func (s *sender) Send(req *pb.PieceUploadRequest) error {
+	if err := s.Wait(); err != nil {
+		return err
+	}
+	s.requests <- req
+	s.inflight = true
+	return nil
+}
+
+// Wait waits for the request in flight to be sent and returns the error of
+// sending it.
+func (s *sender) Wait() error {
+	if !s.inflight {
+		return nil
+	}
+	s.inflight = false
+	return <-s.results
+}
+
+// Close waits for the request in flight and stops the goroutine.
+func (s *sender) Close() {
+	_ = s.Wait()
+	close(s.requests)
+	<-s.stopped
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package piecestore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/pb"
+)
+
+func TestSender(t *testing.T) {
+	stream := &recordingStream{}
+	send := newSender(stream)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, send.Send(&pb.PieceUploadRequest{Chunk: &pb.PieceUploadRequest_Chunk{Offset: int64(i)}}))
+	}
+	require.NoError(t, send.Wait())
+	send.Close()
+
+	require.Len(t, stream.sent, 10)
+	for i, req := range stream.sent {
+		require.EqualValues(t, i, req.Chunk.Offset)
+	}
+}
+
+func TestSender_Error(t *testing.T) {
+	failure := errors.New("failure")
+	stream := &recordingStream{err: failure}
+	send := newSender(stream)
+
+	require.NoError(t, send.Send(&pb.PieceUploadRequest{}))
+	require.ErrorIs(t, send.Send(&pb.PieceUploadRequest{}), failure)
+	require.NoError(t, send.Wait())
+	send.Close()
+
+	require.Len(t, stream.sent, 1)
+}
+
+func TestSender_CloseWaits(t *testing.T) {
+	release := make(chan struct{})
+	stream := &recordingStream{block: release}
+	send := newSender(stream)
+
+	require.NoError(t, send.Send(&pb.PieceUploadRequest{}))
+
+	closed := make(chan struct{})
+	go func() {
+		send.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while a request was in flight")
+	default:
+	}
+
+	close(release)
+	<-closed
+	require.Len(t, stream.sent, 1)
+}
+
+type recordingStream struct {
+	mu    sync.Mutex
+	sent  []*pb.PieceUploadRequest
+	err   error
+	block chan struct{}
+}
+
+func (stream *recordingStream) Context() context.Context { return context.Background() }
+
+func (stream *recordingStream) Close() error { return nil }
+
+func (stream *recordingStream) CloseAndRecv() (*pb.PieceUploadResponse, error) { return nil, nil }
+
+func (stream *recordingStream) Send(req *pb.PieceUploadRequest) error {
+	if stream.block != nil {
+		<-stream.block
+	}
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.sent = append(stream.sent, req)
+	return stream.err
+}
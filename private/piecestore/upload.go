@@ -166,15 +166,29 @@ func (client *upload) write(ctx context.Context, data io.Reader) (hash *pb.Piece
 	// operation. We're going to keep track of how much we've written, and if
 	// the current write requires us to send an order with a larger amount in
 	// it, only then will we sign. Most writes won't include an order.
-
-	backingArray := make([]byte, client.client.config.UploadBufferSize)
+	//
+	// The previous chunk is sent by a separate goroutine while the next one
+	// is read and signed. Reads stay on this goroutine, so the data reader
+	// and the hash are never used concurrently. Two buffers of the upload
+	// buffer size alternate, so the messages keep their size, but every
+	// piece upload uses twice the upload buffer size of memory.
+
+	send := newSender(client.stream)
+	defer send.Close()
+
+	bufferSize := client.client.config.UploadBufferSize
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	buffers := [2][]byte{make([]byte, bufferSize), make([]byte, bufferSize)}
 
 	var orderedSoFar int64
 
+	next := 0
 	done := false
 	for !done {
-		// read the next amount
-		sendData := backingArray
+		// read the next amount, while the previous chunk is being sent
+		sendData := buffers[next]
 		n, readErr := tryReadFull(ctx, data, sendData)
 		if readErr != nil {
 			if !errors.Is(readErr, io.EOF) {
@@ -224,27 +238,38 @@ func (client *upload) write(ctx context.Context, data io.Reader) (hash *pb.Piece
 
 		if done {
 			// combine the last request with the closing data.
+			if err := send.Wait(); err != nil {
+				return nil, client.sendError(err)
+			}
 			return client.commit(ctx, req)
 		}
 
 		// send signed order + data
-		err = client.stream.Send(req)
-		if err != nil {
-			_, closeErr := client.stream.CloseAndRecv()
-			switch {
-			case !errors.Is(err, io.EOF) && closeErr != nil:
-				err = ErrProtocol.Wrap(errs.Combine(err, closeErr))
-			case closeErr != nil:
-				err = ErrProtocol.Wrap(closeErr)
-			}
-
-			return nil, err
+		if err := send.Send(req); err != nil {
+			return nil, client.sendError(err)
 		}
+		next = 1 - next
 	}
 
+	if err := send.Wait(); err != nil {
+		return nil, client.sendError(err)
+	}
 	return client.commit(ctx, &pb.PieceUploadRequest{})
 }
 
+// sendError closes the stream after sending failed and returns the error
+// of the storage node, when there is one.
+func (client *upload) sendError(err error) error {
+	_, closeErr := client.stream.CloseAndRecv()
+	switch {
+	case !errors.Is(err, io.EOF) && closeErr != nil:
+		err = ErrProtocol.Wrap(errs.Combine(err, closeErr))
+	case closeErr != nil:
+		err = ErrProtocol.Wrap(closeErr)
+	}
+	return err
+}
+
 // cancel cancels the uploading.
 func (client *upload) cancel(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
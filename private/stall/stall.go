@@ -0,0 +1,152 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package stall detects uploads where storage nodes stop acknowledging
+// pieces.
+package stall
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/storj"
+)
+
+// Error is the error class for stalled uploads.
+var Error = errs.Class("stalled")
+
+// Detector tracks piece uploads and calls onStall when no piece has been
+// acknowledged within the timeout while piece uploads are in progress.
+type Detector struct {
+	timeout time.Duration
+	onStall func()
+	stop    chan struct{}
+	once    sync.Once
+
+	mu           sync.Mutex
+	lastProgress time.Time
+	pending      map[storj.NodeID]int
+	inProgress   int
+	err          error
+}
+
+// New creates a new detector and starts checking for stalls. Close must be
+// called to stop it.
+func New(timeout time.Duration, onStall func()) *Detector {
+	detector := &Detector{
+		timeout:      timeout,
+		onStall:      onStall,
+		stop:         make(chan struct{}),
+		lastProgress: time.Now(),
+		pending:      map[storj.NodeID]int{},
+	}
+	go detector.run()
+	return detector
+}
+
+// run checks for stalls until the detector is closed.
+func (detector *Detector) run() {
+	interval := detector.timeout / 4
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-detector.stop:
+			return
+		case now := <-ticker.C:
+			if detector.check(now) {
+				detector.onStall()
+				return
+			}
+		}
+	}
+}
+
+// check returns true when the upload became stalled at now.
+func (detector *Detector) check(now time.Time) bool {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+
+	if detector.err != nil || detector.inProgress == 0 {
+		return false
+	}
+	idle := now.Sub(detector.lastProgress)
+	if idle < detector.timeout {
+		return false
+	}
+
+	nodes := make([]string, 0, len(detector.pending))
+	for id := range detector.pending {
+		nodes = append(nodes, id.String())
+	}
+	sort.Strings(nodes)
+
+	detector.err = Error.New("no piece acknowledged for %v, %d piece uploads in progress to nodes %s",
+		idle.Truncate(time.Millisecond), detector.inProgress, strings.Join(nodes, ", "))
+	return true
+}
+
+// Started records that a piece upload to the node started.
+func (detector *Detector) Started(id storj.NodeID) {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+
+	if detector.inProgress == 0 {
+		// time without piece uploads, e.g. while the caller prepares data,
+		// doesn't count as stalled.
+		detector.lastProgress = time.Now()
+	}
+	detector.inProgress++
+	detector.pending[id]++
+}
+
+// Finished records that a piece upload to the node finished. Only successful
+// uploads count as progress.
+func (detector *Detector) Finished(id storj.NodeID, success bool) {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+
+	detector.inProgress--
+	detector.pending[id]--
+	if detector.pending[id] <= 0 {
+		delete(detector.pending, id)
+	}
+	if success {
+		detector.lastProgress = time.Now()
+	}
+}
+
+// Err returns an error describing the stall, or nil when the upload hasn't
+// stalled.
+func (detector *Detector) Err() error {
+	detector.mu.Lock()
+	defer detector.mu.Unlock()
+	return detector.err
+}
+
+// Close stops checking for stalls.
+func (detector *Detector) Close() {
+	detector.once.Do(func() { close(detector.stop) })
+}
+
+type detectorKey struct{}
+
+// WithDetector returns a context that reports piece uploads to detector.
+func WithDetector(ctx context.Context, detector *Detector) context.Context {
+	return context.WithValue(ctx, detectorKey{}, detector)
+}
+
+// FromContext returns the detector of the context or nil.
+func FromContext(ctx context.Context) *Detector {
+	detector, _ := ctx.Value(detectorKey{}).(*Detector)
+	return detector
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package stall_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/uplink/private/stall"
+)
+
+func TestDetector(t *testing.T) {
+	stalled := make(chan struct{})
+	detector := stall.New(200*time.Millisecond, func() { close(stalled) })
+	defer detector.Close()
+
+	ctx := stall.WithDetector(context.Background(), detector)
+	require.Equal(t, detector, stall.FromContext(ctx))
+	require.Nil(t, stall.FromContext(context.Background()))
+
+	// without piece uploads in progress nothing is stalled
+	time.Sleep(300 * time.Millisecond)
+	require.NoError(t, detector.Err())
+
+	fast, slow := testrand.NodeID(), testrand.NodeID()
+	detector.Started(fast)
+	detector.Started(slow)
+
+	// acknowledged pieces count as progress
+	for i := 0; i < 10; i++ {
+		time.Sleep(30 * time.Millisecond)
+		detector.Finished(fast, true)
+		detector.Started(fast)
+	}
+	require.NoError(t, detector.Err())
+	detector.Finished(fast, false)
+
+	select {
+	case <-stalled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload not detected as stalled")
+	}
+
+	err := detector.Err()
+	require.Error(t, err)
+	require.True(t, stall.Error.Has(err))
+	require.Contains(t, err.Error(), slow.String())
+	require.NotContains(t, err.Error(), fast.String())
+}
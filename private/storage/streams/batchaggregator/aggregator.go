@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/spacemonkeygo/monkit/v3"
 	"github.com/zeebo/errs"
@@ -18,11 +19,22 @@ import (
 var mon = monkit.Package()
 
 // Aggregator aggregates batch items to reduce round trips.
+//
+// When the deadline of a flush is too short for all the scheduled items,
+// the commits are issued in a batch of their own first, so they aren't lost
+// to a timeout caused by the other items. The other items are issued
+// afterwards when there is time left for them. Otherwise they are deferred to
+// the next flush, unless ScheduleAndFlush needs the response of its own item,
+// which isn't a commit, in which case it fails. Batches that begin the object
+// are never split, because the items after BeginObject need its stream ID.
 type Aggregator struct {
 	batcher metaclient.Batcher
 
 	mu        sync.Mutex
 	scheduled []metaclient.BatchItem
+	// itemTime is the moving average of the time it took to issue a single
+	// batch item.
+	itemTime time.Duration
 }
 
 // New returns a new aggregator that will aggregate batch items to be issued
@@ -52,7 +64,9 @@ func (a *Aggregator) ScheduleAndFlush(ctx context.Context, batchItem metaclient.
 
 	a.scheduled = append(a.scheduled, batchItem)
 
-	resp, err := a.issueBatchLocked(ctx)
+	// a critical item is issued before the rest, so the rest can be deferred
+	// without losing its response.
+	resp, err := a.issueBatchLocked(ctx, isCritical(batchItem))
 	if err != nil {
 		return nil, err
 	}
@@ -69,11 +83,14 @@ func (a *Aggregator) Flush(ctx context.Context) (err error) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	_, err = a.issueBatchLocked(ctx)
+	_, err = a.issueBatchLocked(ctx, true)
 	return err
 }
 
-func (a *Aggregator) issueBatchLocked(ctx context.Context) (_ []metaclient.BatchResponse, err error) {
+// issueBatchLocked issues the scheduled batch items. When deferRest is true,
+// non-critical items that don't fit the deadline are scheduled again instead
+// of failing the batch.
+func (a *Aggregator) issueBatchLocked(ctx context.Context, deferRest bool) (_ []metaclient.BatchResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 	batchItems := a.scheduled
 	a.scheduled = a.scheduled[:0]
@@ -86,7 +103,116 @@ func (a *Aggregator) issueBatchLocked(ctx context.Context) (_ []metaclient.Batch
 		testuplink.Log(ctx, "Flush batch item:", batchItemTypeName(batchItem))
 	}
 
-	return a.batcher.Batch(ctx, batchItems...)
+	if a.hasTimeLocked(ctx, len(batchItems)) || beginsObject(batchItems) {
+		return a.issueLocked(ctx, batchItems)
+	}
+
+	critical, rest := splitCritical(batchItems)
+	if len(critical) == 0 || len(rest) == 0 {
+		return a.issueLocked(ctx, batchItems)
+	}
+
+	mon.Event("batch_split_for_deadline")
+	testuplink.Log(ctx, "Splitting batch for deadline")
+
+	criticalResponses, err := a.issueLocked(ctx, critical)
+	if err != nil {
+		return nil, err
+	}
+
+	if !a.hasTimeLocked(ctx, len(rest)) {
+		if !deferRest {
+			return nil, errs.Wrap(context.DeadlineExceeded)
+		}
+		mon.Event("batch_deferred_for_deadline")
+		testuplink.Log(ctx, "Deferring", len(rest), "batch items for deadline")
+		a.scheduled = append(a.scheduled, rest...)
+		return criticalResponses, nil
+	}
+
+	restResponses, err := a.issueLocked(ctx, rest)
+	if err != nil {
+		return nil, err
+	}
+	return mergeResponses(batchItems, criticalResponses, restResponses), nil
+}
+
+// issueLocked issues a single batch and updates the time estimate of items.
+func (a *Aggregator) issueLocked(ctx context.Context, batchItems []metaclient.BatchItem) ([]metaclient.BatchResponse, error) {
+	start := time.Now()
+	responses, err := a.batcher.Batch(ctx, batchItems...)
+	if err == nil {
+		itemTime := time.Since(start) / time.Duration(len(batchItems))
+		if a.itemTime == 0 {
+			a.itemTime = itemTime
+		} else {
+			a.itemTime = (3*a.itemTime + itemTime) / 4
+		}
+	}
+	return responses, err
+}
+
+// hasTimeLocked returns whether the deadline of ctx leaves enough time to
+// issue count items, based on the previous batches.
+func (a *Aggregator) hasTimeLocked(ctx context.Context, count int) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok || a.itemTime == 0 {
+		return true
+	}
+	return time.Until(deadline) >= a.itemTime*time.Duration(count)
+}
+
+// isCritical returns whether the batch item commits data, which would be lost
+// when the batch times out.
+func isCritical(batchItem metaclient.BatchItem) bool {
+	switch batchItem.(type) {
+	case *metaclient.CommitSegmentParams, *metaclient.MakeInlineSegmentParams, *metaclient.CommitObjectParams:
+		return true
+	default:
+		return false
+	}
+}
+
+// beginsObject returns whether batchItems contain a BeginObject, whose stream
+// ID is needed by the items after it.
+func beginsObject(batchItems []metaclient.BatchItem) bool {
+	for _, batchItem := range batchItems {
+		if _, ok := batchItem.(*metaclient.BeginObjectParams); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCritical splits batchItems into the critical and the other items,
+// keeping their order.
+func splitCritical(batchItems []metaclient.BatchItem) (critical, rest []metaclient.BatchItem) {
+	for _, batchItem := range batchItems {
+		if isCritical(batchItem) {
+			critical = append(critical, batchItem)
+		} else {
+			rest = append(rest, batchItem)
+		}
+	}
+	return critical, rest
+}
+
+// mergeResponses orders the responses of the critical and the other items
+// like batchItems.
+func mergeResponses(batchItems []metaclient.BatchItem, critical, rest []metaclient.BatchResponse) []metaclient.BatchResponse {
+	responses := make([]metaclient.BatchResponse, 0, len(critical)+len(rest))
+	for _, batchItem := range batchItems {
+		if isCritical(batchItem) {
+			if len(critical) > 0 {
+				responses = append(responses, critical[0])
+				critical = critical[1:]
+			}
+		} else if len(rest) > 0 {
+			responses = append(responses, rest[0])
+			rest = rest[1:]
+		}
+	}
+	return responses
 }
 
 func batchItemTypeName(batchItem metaclient.BatchItem) string {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -112,8 +113,125 @@ func TestAggregator(t *testing.T) {
 	})
 }
 
+func TestAggregator_Deadline(t *testing.T) {
+	commit := &metaclient.CommitSegmentParams{SegmentID: []byte("A")}
+	begin := &metaclient.BeginSegmentParams{StreamID: []byte("B")}
+	response := &pb.BatchResponseItem{Response: &pb.BatchResponseItem_SegmentBegin{SegmentBegin: &pb.BeginSegmentResponse{SegmentId: []byte("1")}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	t.Run("enough time issues a single batch", func(t *testing.T) {
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response, response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Second
+		aggregator.Schedule(begin)
+
+		_, err := aggregator.ScheduleAndFlush(ctx, commit)
+		require.NoError(t, err)
+		assert.Equal(t, [][]metaclient.BatchItem{{begin, commit}}, batcher.calls)
+	})
+
+	t.Run("short deadline issues commits first", func(t *testing.T) {
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = 40 * time.Second
+		aggregator.Schedule(commit)
+
+		resp, err := aggregator.ScheduleAndFlush(ctx, begin)
+		require.NoError(t, err)
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}, {begin}}, batcher.calls)
+		assert.Equal(t, metaclient.MakeBatchResponse(begin.BatchItem(), response), *resp)
+	})
+
+	t.Run("short deadline without commits issues a single batch", func(t *testing.T) {
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response, response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(begin)
+
+		_, err := aggregator.ScheduleAndFlush(ctx, begin)
+		require.NoError(t, err)
+		assert.Equal(t, [][]metaclient.BatchItem{{begin, begin}}, batcher.calls)
+	})
+
+	t.Run("short deadline fails items that don't fit after the commits", func(t *testing.T) {
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(commit)
+
+		_, err := aggregator.ScheduleAndFlush(ctx, begin)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}}, batcher.calls)
+	})
+
+	t.Run("short deadline defers items after a critical item", func(t *testing.T) {
+		commitResponse := &pb.BatchResponseItem{Response: &pb.BatchResponseItem_SegmentCommit{SegmentCommit: &pb.CommitSegmentResponse{}}}
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{commitResponse}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(begin)
+
+		resp, err := aggregator.ScheduleAndFlush(ctx, commit)
+		require.NoError(t, err)
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}}, batcher.calls)
+		assert.Equal(t, metaclient.MakeBatchResponse(commit.BatchItem(), commitResponse), *resp)
+
+		batcher.responses = []*pb.BatchResponseItem{response}
+		require.NoError(t, aggregator.Flush(context.Background()))
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}, {begin}}, batcher.calls)
+	})
+
+	t.Run("short deadline defers items of a flush", func(t *testing.T) {
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(commit)
+		aggregator.Schedule(begin)
+
+		require.NoError(t, aggregator.Flush(ctx))
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}}, batcher.calls)
+
+		require.NoError(t, aggregator.Flush(context.Background()))
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}, {begin}}, batcher.calls)
+	})
+
+	t.Run("short deadline does not split begin object", func(t *testing.T) {
+		beginObject := &metaclient.BeginObjectParams{Bucket: []byte("bucket")}
+		batcher := &fakeBatcher{responses: []*pb.BatchResponseItem{response, response}}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(beginObject)
+		aggregator.Schedule(commit)
+
+		require.NoError(t, aggregator.Flush(ctx))
+		assert.Equal(t, [][]metaclient.BatchItem{{beginObject, commit}}, batcher.calls)
+	})
+
+	t.Run("failed commits are not followed by other items", func(t *testing.T) {
+		batcher := &fakeBatcher{err: errors.New("oh no")}
+
+		aggregator := New(batcher)
+		aggregator.itemTime = time.Hour
+		aggregator.Schedule(commit)
+
+		_, err := aggregator.ScheduleAndFlush(ctx, begin)
+		assert.EqualError(t, err, "oh no")
+		assert.Equal(t, [][]metaclient.BatchItem{{commit}}, batcher.calls)
+	})
+}
+
 type fakeBatcher struct {
 	items     []metaclient.BatchItem
+	calls     [][]metaclient.BatchItem
 	responses []*pb.BatchResponseItem
 	err       error
 }
@@ -123,6 +241,7 @@ func (mi *fakeBatcher) Batch(ctx context.Context, items ...metaclient.BatchItem)
 		return nil, errs.New("test/programmer error: batch should never be issued with no items")
 	}
 	mi.items = items
+	mi.calls = append(mi.calls, append([]metaclient.BatchItem(nil), items...))
 	if mi.err != nil {
 		return nil, mi.err
 	}
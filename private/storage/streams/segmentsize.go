@@ -0,0 +1,29 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+// SegmentSizeFor returns the segment size to use for an object of
+// contentLength bytes, when segments may be at most maxSegmentSize bytes.
+//
+// The object is split into the least possible number of segments and the
+// segments are made as equal in size as possible, rounded up to a multiple
+// of blockSize. This avoids a nearly empty last segment and the buffers of
+// a full segment for it. When contentLength is unknown (zero) or fits into
+// a single segment, maxSegmentSize is returned, so a wrong hint can at
+// most double the number of segments.
+func SegmentSizeFor(maxSegmentSize, contentLength, blockSize int64) int64 {
+	if contentLength <= maxSegmentSize || maxSegmentSize <= 0 {
+		return maxSegmentSize
+	}
+
+	segments := (contentLength + maxSegmentSize - 1) / maxSegmentSize
+	segmentSize := (contentLength + segments - 1) / segments
+	if blockSize > 0 {
+		segmentSize = (segmentSize + blockSize - 1) / blockSize * blockSize
+	}
+	if segmentSize > maxSegmentSize {
+		return maxSegmentSize
+	}
+	return segmentSize
+}
@@ -0,0 +1,35 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/uplink/private/storage/streams"
+)
+
+func TestSegmentSizeFor(t *testing.T) {
+	for _, tc := range []struct {
+		max, length, block int64
+		expected           int64
+	}{
+		{max: 64, length: 0, block: 4, expected: 64},
+		{max: 64, length: 10, block: 4, expected: 64},
+		{max: 64, length: 64, block: 4, expected: 64},
+		{max: 64, length: 65, block: 4, expected: 36},
+		{max: 64, length: 128, block: 4, expected: 64},
+		{max: 64, length: 129, block: 1, expected: 43},
+		{max: 64, length: 129, block: 0, expected: 43},
+		{max: 64, length: 127, block: 48, expected: 64},
+	} {
+		size := streams.SegmentSizeFor(tc.max, tc.length, tc.block)
+		assert.Equal(t, tc.expected, size, "%+v", tc)
+
+		segments := (tc.length + size - 1) / size
+		minimum := (tc.length + tc.max - 1) / tc.max
+		assert.Equal(t, minimum, segments, "%+v", tc)
+	}
+}
@@ -33,11 +33,19 @@ type Scheduler interface {
 	Join(ctx context.Context) (scheduler.Handle, bool)
 }
 
+// SuccessThresholder is implemented by piece putters that need more
+// successful piece uploads than the optimal threshold before the remaining
+// piece uploads are cancelled, e.g. the ecclient with a configured long tail.
+type SuccessThresholder interface {
+	SuccessThreshold(rs eestream.RedundancyStrategy, limits int) int
+}
+
 // Begin starts a segment upload identified by the segment ID provided in the
 // beginSegment response. The returned upload will complete when enough piece
-// uploads to fulfill the optimal threshold for the segment redundancy strategy
+// uploads to fulfill the success threshold for the segment redundancy strategy
 // plus a small long tail margin. It cancels remaining piece uploads once that
-// threshold has been hit.
+// threshold has been hit. The success threshold is the optimal threshold,
+// unless piecePutter implements SuccessThresholder.
 func Begin(ctx context.Context,
 	beginSegment *metaclient.BeginSegmentResponse,
 	segment splitter.Segment,
@@ -82,11 +90,16 @@ func Begin(ctx context.Context,
 		return nil, errs.New("begin segment response needs at least %d limits to meet optimal threshold but has %d", optimalThreshold, len(beginSegment.Limits))
 	}
 
+	successThreshold := optimalThreshold
+	if thresholder, ok := piecePutter.(SuccessThresholder); ok {
+		successThreshold = thresholder.SuccessThreshold(beginSegment.RedundancyStrategy, len(beginSegment.Limits))
+	}
+
 	uploaderCount := len(beginSegment.Limits)
 	if longTailMargin >= 0 {
-		// The number of uploads is enough to satisfy the optimal threshold plus
+		// The number of uploads is enough to satisfy the success threshold plus
 		// a small long tail margin, capped by the number of limits.
-		uploaderCount = optimalThreshold + longTailMargin
+		uploaderCount = successThreshold + longTailMargin
 		if uploaderCount > len(beginSegment.Limits) {
 			uploaderCount = len(beginSegment.Limits)
 		}
@@ -133,10 +146,10 @@ func Begin(ctx context.Context,
 			uploaded, err := pieceupload.UploadOne(longTailCtx, ctx, mgr, piecePutter, beginSegment.PiecePrivateKey)
 			results <- segmentResult{uploaded: uploaded, err: err}
 			if uploaded {
-				// Piece upload was successful. If we have met the optimal threshold, we
+				// Piece upload was successful. If we have met the success threshold, we
 				// can cancel the rest.
-				if int(atomic.AddInt32(&successful, 1)) == optimalThreshold {
-					testuplink.Log(ctx, "Segment reached optimal threshold of", optimalThreshold, "pieces.")
+				if int(atomic.AddInt32(&successful, 1)) == successThreshold {
+					testuplink.Log(ctx, "Segment reached success threshold of", successThreshold, "pieces.")
 					cancel()
 				}
 			}
@@ -404,3 +404,52 @@ func mustNewRedundancyStrategy() eestream.RedundancyStrategy {
 	}
 	return rs
 }
+
+func TestBegin_SuccessThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		desc                string
+		beginSegment        *metaclient.BeginSegmentResponse
+		longTailMargin      int
+		successThreshold    int
+		expectUploaderCount int
+		expectPieces        int
+	}{
+		{
+			desc:                "waits for the success threshold",
+			beginSegment:        makeBeginSegment(fastKind, fastKind, fastKind, fastKind, fastKind),
+			successThreshold:    totalShares,
+			expectUploaderCount: totalShares,
+			expectPieces:        totalShares,
+		},
+		{
+			desc:                "slow piece uploads are cancelled after success threshold hit",
+			beginSegment:        makeBeginSegment(fastKind, fastKind, fastKind, fastKind, slowKind),
+			longTailMargin:      1,
+			successThreshold:    totalShares,
+			expectUploaderCount: totalShares + 1,
+			expectPieces:        totalShares,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			sched := newWrappedScheduler()
+			piecePutter := thresholdPiecePutter{threshold: tc.successThreshold}
+
+			upload, err := Begin(context.Background(), tc.beginSegment, new(fakeSegment), new(fakeLimitsExchanger), piecePutter, sched, tc.longTailMargin)
+			require.NoError(t, err)
+
+			commitSegment, err := upload.Wait()
+			require.NoError(t, err)
+			require.Len(t, commitSegment.UploadResult, tc.expectPieces)
+			require.NoError(t, sched.check(tc.expectUploaderCount))
+		})
+	}
+}
+
+type thresholdPiecePutter struct {
+	fakePiecePutter
+	threshold int
+}
+
+func (p thresholdPiecePutter) SuccessThreshold(rs eestream.RedundancyStrategy, limits int) int {
+	return p.threshold
+}
@@ -98,8 +98,12 @@ func NewStreamStore(metainfo *metaclient.Client, ec ecclient.Client, segmentSize
 	}, nil
 }
 
-// Close closes the underlying resources passed to the metainfo DB.
+// Close closes the underlying resources passed to the metainfo DB. Closing a
+// nil store does nothing.
 func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
 	return s.metainfo.Close()
 }
 
@@ -855,7 +859,7 @@ func decryptRanger(ctx context.Context, rr ranger.Ranger, plainSize int64, encry
 		return ranger.ByteRanger(data), nil
 	}
 
-	rd, err = encryption.Transform(rr, decrypter)
+	rd, err = ParallelTransform(rr, decrypter, transformWorkers())
 	if err != nil {
 		return nil, err
 	}
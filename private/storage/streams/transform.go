@@ -0,0 +1,305 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+
+	"storj.io/common/encryption"
+	"storj.io/common/ranger"
+	"storj.io/common/readcloser"
+)
+
+const (
+	// maxTransformWorkers limits the number of goroutines used to decrypt a
+	// single download.
+	maxTransformWorkers = 8
+	// blocksPerTransformWorker is the number of blocks read ahead of the
+	// reader for every worker, so the workers are kept busy while the reader
+	// consumes the transformed blocks.
+	blocksPerTransformWorker = 16
+)
+
+// transformWorkers returns the number of workers to use for transforming.
+func transformWorkers() int {
+	workers := runtime.GOMAXPROCS(0)
+	if workers > maxTransformWorkers {
+		workers = maxTransformWorkers
+	}
+	return workers
+}
+
+// ParallelTransform is like encryption.Transform, but the reader returned by
+// Range transforms blocks with the specified number of workers. The
+// Transformer must be safe for concurrent use, which is the case for the
+// encryption and decryption transformers.
+func ParallelTransform(rr ranger.Ranger, t encryption.Transformer, workers int) (ranger.Ranger, error) {
+	if workers <= 1 {
+		return encryption.Transform(rr, t)
+	}
+	if rr.Size()%int64(t.InBlockSize()) != 0 {
+		return nil, encryption.Error.New("invalid transformer and range reader combination." +
+			"the range reader size is not a multiple of the block size")
+	}
+	return &parallelTransformedRanger{rr: rr, t: t, workers: workers}, nil
+}
+
+type parallelTransformedRanger struct {
+	rr      ranger.Ranger
+	t       encryption.Transformer
+	workers int
+}
+
+// Size implements ranger.Ranger.
+func (t *parallelTransformedRanger) Size() int64 {
+	blocks := t.rr.Size() / int64(t.t.InBlockSize())
+	return blocks * int64(t.t.OutBlockSize())
+}
+
+// Range implements ranger.Ranger.
+func (t *parallelTransformedRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	firstBlock, blockCount := encryption.CalcEncompassingBlocks(offset, length, t.t.OutBlockSize())
+	if blockCount == 0 {
+		return io.NopCloser(bytes.NewReader(nil)), nil
+	}
+
+	r, err := t.rr.Range(ctx,
+		firstBlock*int64(t.t.InBlockSize()),
+		blockCount*int64(t.t.InBlockSize()))
+	if err != nil {
+		return nil, err
+	}
+
+	// the part of the first block that was not requested is skipped.
+	skip := offset - firstBlock*int64(t.t.OutBlockSize())
+	tr := newParallelTransformedReader(r, t.t, t.workers, firstBlock, blockCount, skip)
+	return readcloser.LimitReadCloser(tr, length), nil
+}
+
+// parallelTransformedReader reads blocks in a goroutine and transforms them
+// with a pool of workers. The goroutines are started by the first Read.
+//
+// Every block is handed to the reader as soon as it's transformed, while the
+// following blocks are still being read and transformed, so the first bytes
+// are delivered as soon as the first block arrived.
+type parallelTransformedReader struct {
+	r          io.ReadCloser
+	t          encryption.Transformer
+	workers    int
+	firstBlock int64
+	blockCount int64
+	skip       int64
+
+	// ins and outs hold the buffers of the blocks, which were transformed
+	// and consumed by the reader, so they are reused by the following blocks.
+	ins  bufferList
+	outs bufferList
+
+	// mu guards started and closed, so Close doesn't race with starting
+	// the goroutines.
+	mu      sync.Mutex
+	started bool
+	closed  bool
+
+	// pending contains the results of the blocks in the order of the blocks.
+	// Its capacity limits how far the blocks are read ahead.
+	pending chan chan transformedBlock
+	stop    chan struct{}
+	wg      sync.WaitGroup
+
+	block   []byte
+	current []byte
+	err     error
+}
+
+// transformJob is a block to be transformed by a worker.
+type transformJob struct {
+	in       []byte
+	blockNum int64
+	result   chan<- transformedBlock
+}
+
+// transformedBlock is the result of transforming a block.
+type transformedBlock struct {
+	out []byte
+	err error
+}
+
+// bufferList is a free-list of block buffers.
+type bufferList chan []byte
+
+// get returns a buffer of the specified size, reusing a free one if
+// available.
+func (list bufferList) get(size int) []byte {
+	select {
+	case buf := <-list:
+		if cap(buf) >= size {
+			return buf[:size]
+		}
+	default:
+	}
+	return make([]byte, size)
+}
+
+// put adds buf to the free buffers, unless there are enough of them.
+func (list bufferList) put(buf []byte) {
+	select {
+	case list <- buf:
+	default:
+	}
+}
+
+// newParallelTransformedReader returns a reader that transforms blockCount
+// blocks read from r, beginning with block number firstBlock, and skips the
+// first skip bytes of the result.
+func newParallelTransformedReader(r io.ReadCloser, t encryption.Transformer, workers int, firstBlock, blockCount, skip int64) *parallelTransformedReader {
+	// every block read ahead, queued for or transformed by a worker may
+	// hold a buffer.
+	buffers := workers*blocksPerTransformWorker + 2*workers
+	return &parallelTransformedReader{
+		r:          r,
+		t:          t,
+		workers:    workers,
+		firstBlock: firstBlock,
+		blockCount: blockCount,
+		skip:       skip,
+		ins:        make(bufferList, buffers),
+		outs:       make(bufferList, buffers),
+		pending:    make(chan chan transformedBlock, workers*blocksPerTransformWorker),
+		stop:       make(chan struct{}),
+	}
+}
+
+// start starts reading and transforming the blocks, unless they were already
+// started or the reader was closed.
+func (tr *parallelTransformedReader) start() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.closed {
+		return encryption.Error.New("reader is closed")
+	}
+	if tr.started {
+		return nil
+	}
+	tr.started = true
+
+	jobs := make(chan transformJob, tr.workers)
+	tr.wg.Add(1 + tr.workers)
+	go func() {
+		defer tr.wg.Done()
+		defer close(jobs)
+		defer close(tr.pending)
+		tr.readBlocks(jobs)
+	}()
+	for i := 0; i < tr.workers; i++ {
+		go func() {
+			defer tr.wg.Done()
+			for job := range jobs {
+				out, err := tr.t.Transform(tr.outs.get(0), job.in, job.blockNum)
+				if err != nil && !errors.Is(err, io.EOF) {
+					err = encryption.Error.Wrap(err)
+				}
+				tr.ins.put(job.in)
+				job.result <- transformedBlock{out: out, err: err}
+			}
+		}()
+	}
+	return nil
+}
+
+// readBlocks reads the blocks and queues them for the workers until all
+// blocks were read, reading fails or the reader is closed.
+func (tr *parallelTransformedReader) readBlocks(jobs chan<- transformJob) {
+	inSize := tr.t.InBlockSize()
+	for i := int64(0); i < tr.blockCount; i++ {
+		result := make(chan transformedBlock, 1)
+		select {
+		case tr.pending <- result:
+		case <-tr.stop:
+			return
+		}
+
+		in := tr.ins.get(inSize)
+		if _, err := io.ReadFull(tr.r, in); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			result <- transformedBlock{err: err}
+			return
+		}
+
+		select {
+		case jobs <- transformJob{in: in, blockNum: tr.firstBlock + i, result: result}:
+		case <-tr.stop:
+			// a Read may already wait for the result.
+			result <- transformedBlock{err: encryption.Error.New("reader is closed")}
+			return
+		}
+	}
+}
+
+// Read implements io.Reader.
+func (tr *parallelTransformedReader) Read(p []byte) (n int, err error) {
+	if err := tr.start(); err != nil {
+		return 0, err
+	}
+
+	for len(tr.current) == 0 {
+		if tr.block != nil {
+			tr.outs.put(tr.block)
+			tr.block = nil
+		}
+		if tr.err != nil {
+			return 0, tr.err
+		}
+
+		result, ok := <-tr.pending
+		if !ok {
+			tr.err = io.EOF
+			if tr.skip > 0 {
+				tr.err = io.ErrUnexpectedEOF
+			}
+			continue
+		}
+
+		block := <-result
+		tr.block, tr.current, tr.err = block.out, block.out, block.err
+		if tr.skip > 0 {
+			skip := tr.skip
+			if skip > int64(len(tr.current)) {
+				skip = int64(len(tr.current))
+			}
+			tr.current = tr.current[skip:]
+			tr.skip -= skip
+		}
+	}
+
+	n = copy(p, tr.current)
+	tr.current = tr.current[n:]
+	return n, nil
+}
+
+// Close implements io.Closer.
+func (tr *parallelTransformedReader) Close() (err error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	if tr.closed {
+		return nil
+	}
+	tr.closed = true
+
+	close(tr.stop)
+	err = tr.r.Close()
+	tr.wg.Wait()
+	return err
+}
@@ -0,0 +1,234 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/encryption"
+	"storj.io/common/ranger"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/uplink/private/storage/streams"
+)
+
+func TestParallelTransform(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	const blockSize = 1024
+
+	key := testrand.Key()
+	nonce := testrand.Nonce()
+
+	encrypter, err := encryption.NewEncrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+	decrypter, err := encryption.NewDecrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(300 * encrypter.InBlockSize())
+	encrypted, err := io.ReadAll(encryption.TransformReader(io.NopCloser(bytes.NewReader(data)), encrypter, 0))
+	require.NoError(t, err)
+
+	for _, workers := range []int{1, 2, 3, 8} {
+		rr, err := streams.ParallelTransform(ranger.ByteRanger(encrypted), decrypter, workers)
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), rr.Size())
+
+		for _, r := range []struct{ offset, length int64 }{
+			{0, int64(len(data))},
+			{0, 0},
+			{1, 1},
+			{100, 5000},
+			{int64(decrypter.OutBlockSize()), int64(decrypter.OutBlockSize())},
+			{int64(len(data)) - 10, 10},
+			{12345, int64(len(data)) - 12345},
+		} {
+			reader, err := rr.Range(ctx, r.offset, r.length)
+			require.NoError(t, err)
+			got, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+			require.Equal(t, data[r.offset:r.offset+r.length], got, "workers=%d offset=%d length=%d", workers, r.offset, r.length)
+		}
+	}
+
+	// closing stops reading ahead
+	rr, err := streams.ParallelTransform(ranger.ByteRanger(encrypted), decrypter, 4)
+	require.NoError(t, err)
+	reader, err := rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	_, err = io.ReadFull(reader, make([]byte, 10))
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+
+	// corrupted data must fail to decrypt
+	corrupted := append([]byte{}, encrypted...)
+	corrupted[len(corrupted)/2] ^= 0xFF
+
+	rr, err = streams.ParallelTransform(ranger.ByteRanger(corrupted), decrypter, 4)
+	require.NoError(t, err)
+	reader, err = rr.Range(ctx, 0, rr.Size())
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	require.True(t, encryption.ErrDecryptFailed.Has(err))
+	require.NoError(t, reader.Close())
+}
+
+func TestParallelTransform_FirstBlock(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	const blockSize = 1024
+
+	key := testrand.Key()
+	nonce := testrand.Nonce()
+
+	encrypter, err := encryption.NewEncrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+	decrypter, err := encryption.NewDecrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(100 * encrypter.InBlockSize())
+	encrypted, err := io.ReadAll(encryption.TransformReader(io.NopCloser(bytes.NewReader(data)), encrypter, 0))
+	require.NoError(t, err)
+
+	// only the first block is available until the test releases the rest
+	release := make(chan struct{})
+	rr := &gatedRanger{
+		Ranger:  ranger.ByteRanger(encrypted),
+		first:   int64(encrypter.OutBlockSize()),
+		release: release,
+	}
+
+	decrypted, err := streams.ParallelTransform(rr, decrypter, 4)
+	require.NoError(t, err)
+
+	reader, err := decrypted.Range(ctx, 0, decrypted.Size())
+	require.NoError(t, err)
+
+	buf := make([]byte, decrypter.OutBlockSize())
+	_, err = io.ReadFull(reader, buf)
+	require.NoError(t, err)
+	require.Equal(t, data[:len(buf)], buf)
+
+	close(release)
+
+	rest, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, data[len(buf):], rest)
+	require.NoError(t, reader.Close())
+}
+
+func TestParallelTransform_Lazy(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	const blockSize = 1024
+
+	key := testrand.Key()
+	nonce := testrand.Nonce()
+
+	encrypter, err := encryption.NewEncrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+	decrypter, err := encryption.NewDecrypter(storj.EncAESGCM, &key, &nonce, blockSize)
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(10 * encrypter.InBlockSize())
+	encrypted, err := io.ReadAll(encryption.TransformReader(io.NopCloser(bytes.NewReader(data)), encrypter, 0))
+	require.NoError(t, err)
+
+	// the blocks after the first one are only available once released, so
+	// started goroutines keep running.
+	release := make(chan struct{})
+	defer close(release)
+	rr, err := streams.ParallelTransform(&gatedRanger{
+		Ranger:  ranger.ByteRanger(encrypted),
+		first:   int64(encrypter.OutBlockSize()),
+		release: release,
+	}, decrypter, 8)
+	require.NoError(t, err)
+
+	before := runtime.NumGoroutine()
+
+	// readers that are never read must not start any goroutines, so they
+	// don't leak when they aren't closed either.
+	for i := 0; i < 10; i++ {
+		_, err := rr.Range(ctx, 100, 5000)
+		require.NoError(t, err)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	// the goroutines are started by the first read and stopped by close.
+	reader, err := rr.Range(ctx, 100, 5000)
+	require.NoError(t, err)
+	_, err = io.ReadFull(reader, make([]byte, 10))
+	require.NoError(t, err)
+	require.Greater(t, runtime.NumGoroutine(), before)
+	require.NoError(t, reader.Close())
+	require.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	// a closed reader can't be read.
+	reader, err = rr.Range(ctx, 100, 5000)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	_, err = reader.Read(make([]byte, 10))
+	require.Error(t, err)
+}
+
+// gatedRanger returns readers that block after the first bytes until release
+// is closed or the reader is closed.
+type gatedRanger struct {
+	ranger.Ranger
+	first   int64
+	release chan struct{}
+}
+
+func (rr *gatedRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	reader, err := rr.Ranger.Range(ctx, offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &gatedReader{
+		ReadCloser: reader,
+		remaining:  rr.first,
+		release:    rr.release,
+		closed:     make(chan struct{}),
+	}, nil
+}
+
+type gatedReader struct {
+	io.ReadCloser
+	remaining int64
+	release   chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		select {
+		case <-r.release:
+		case <-r.closed:
+			return 0, io.ErrClosedPipe
+		}
+		return r.ReadCloser.Read(p)
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.remaining -= int64(n)
+	return n, err
+}
+
+func (r *gatedReader) Close() error {
+	r.closeOnce.Do(func() { close(r.closed) })
+	return r.ReadCloser.Close()
+}
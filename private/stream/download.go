@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha1"
+	"errors"
 	"io"
 
 	"storj.io/common/encryption"
@@ -15,11 +16,16 @@ import (
 	"storj.io/eventkit"
 	"storj.io/picobuf"
 	"storj.io/uplink/private/metaclient"
+	"storj.io/uplink/private/piecestore"
 	"storj.io/uplink/private/storage/streams"
 )
 
 const (
 	maxDecryptionRetries = 6
+
+	// maxOrderLimitRetries is the number of times the order limits are
+	// refetched without successfully reading any data in between.
+	maxOrderLimitRetries = 3
 )
 
 var (
@@ -37,6 +43,7 @@ type Download struct {
 	closed  bool
 
 	decryptionRetries int
+	orderLimitRetries int
 }
 
 // NewDownload creates new stream download.
@@ -92,6 +99,7 @@ func (download *Download) Read(data []byte) (n int, err error) {
 
 	if err == nil && n > 0 {
 		download.decryptionRetries = 0
+		download.orderLimitRetries = 0
 
 	} else if encryption.ErrDecryptFailed.Has(err) {
 		evs.Event("decryption-failure",
@@ -111,6 +119,20 @@ func (download *Download) Read(data []byte) (n int, err error) {
 
 			err = download.resetReader(true)
 		}
+	} else if download.hasExpiredLimits(err) {
+		evs.Event("order-limit-refresh",
+			eventkit.Int64("order-limit-retries", int64(download.orderLimitRetries)),
+			eventkit.Int64("offset", download.offset),
+			eventkit.Int64("length", download.length),
+		)
+
+		download.orderLimitRetries++
+
+		// long-running downloads may outlive the order limits, so get new
+		// ones and continue from the current offset.
+		download.info.DownloadedSegments = nil
+
+		err = download.resetReader(false)
 	}
 
 	return n, err
@@ -131,6 +153,21 @@ func (download *Download) Close() error {
 	return download.reader.Close()
 }
 
+// hasExpiredLimits returns whether a read failed because storage nodes
+// rejected the order limits of the reader as expired, and the read should be
+// retried with new order limits.
+func (download *Download) hasExpiredLimits(err error) bool {
+	switch {
+	case err == nil || errors.Is(err, io.EOF):
+		return false
+	case download.ctx.Err() != nil:
+		return false
+	case download.orderLimitRetries >= maxOrderLimitRetries:
+		return false
+	}
+	return piecestore.IsOrderLimitExpired(err)
+}
+
 func (download *Download) resetReader(nextSegmentErrorDetection bool) error {
 	if download.reader != nil {
 		err := download.reader.Close()
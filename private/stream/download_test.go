@@ -4,10 +4,17 @@
 package stream
 
 import (
+	"context"
 	"encoding/hex"
+	"errors"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/rpc/rpcstatus"
 )
 
 func TestMaybeSatStreamID(t *testing.T) {
@@ -32,3 +39,26 @@ func TestMaybeSatStreamID(t *testing.T) {
 
 	require.Equal(t, hex.EncodeToString(maybeSatStreamID(bytes)), "")
 }
+
+func TestHasExpiredLimits(t *testing.T) {
+	ctx := context.Background()
+	expired := errs.Combine(
+		errors.New("connection refused"),
+		rpcstatus.Errorf(rpcstatus.InvalidArgument, "order expired: %v", time.Now()),
+	)
+
+	download := &Download{ctx: ctx}
+	require.True(t, download.hasExpiredLimits(expired))
+	require.False(t, download.hasExpiredLimits(nil))
+	require.False(t, download.hasExpiredLimits(io.EOF))
+	require.False(t, download.hasExpiredLimits(errors.New("failure")))
+	require.False(t, download.hasExpiredLimits(rpcstatus.Error(rpcstatus.InvalidArgument, "order limit is negative")))
+
+	download.orderLimitRetries = maxOrderLimitRetries
+	require.False(t, download.hasExpiredLimits(expired))
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	canceled := &Download{ctx: canceledCtx}
+	require.False(t, canceled.hasExpiredLimits(expired))
+}
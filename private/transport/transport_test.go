@@ -24,3 +24,17 @@ func TestExpose(t *testing.T) {
 	DisableBackgroundQoS(&cfg, false)
 	require.False(t, sudo.Sudo(reflect.ValueOf(cfg).FieldByName("disableBackgroundQoS")).Interface().(bool))
 }
+
+func TestUseWebSocketProxy(t *testing.T) {
+	var cfg uplink.Config
+	require.NoError(t, UseWebSocketProxy(&cfg, "wss://proxy.example.test/connect?token=abc"))
+	//nolint:staticcheck // deprecated okay.
+	require.NotNil(t, cfg.DialContext)
+
+	dialer := &webSocketDialer{proxyURL: "wss://proxy.example.test/connect?token=abc"}
+	wsURL, err := dialer.webSocketURL("node.example.test:7777")
+	require.NoError(t, err)
+	require.Equal(t, "wss://proxy.example.test/connect?address=node.example.test%3A7777&token=abc", wsURL)
+
+	require.Error(t, UseWebSocketProxy(&cfg, "://invalid"))
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package transport
+
+import (
+	"net"
+	"net/url"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink"
+)
+
+// ErrWebSocket is the error class for WebSocket connections.
+var ErrWebSocket = errs.Class("websocket")
+
+// UseWebSocketProxy configures config to connect to the satellite and storage
+// nodes through a WebSocket proxy, which forwards the connection to the
+// address in the "address" query parameter of the WebSocket URL.
+//
+// Browsers can't open TCP connections, so this is required for uplinks
+// compiled with GOOS=js GOARCH=wasm. The data is still encrypted with TLS
+// end-to-end, so the proxy doesn't need to be trusted.
+//
+// WebSocket connections are only supported on js/wasm.
+func UseWebSocketProxy(config *uplink.Config, proxyURL string) error {
+	if _, err := url.Parse(proxyURL); err != nil {
+		return ErrWebSocket.Wrap(err)
+	}
+
+	dialer := &webSocketDialer{proxyURL: proxyURL}
+	//lint:ignore SA1019 deprecated okay,
+	//nolint:staticcheck // deprecated okay.
+	config.DialContext = dialer.DialContext
+	return nil
+}
+
+// webSocketDialer dials connections through a WebSocket proxy.
+type webSocketDialer struct {
+	proxyURL string
+}
+
+// webSocketURL returns the WebSocket URL for connecting to address.
+func (dialer *webSocketDialer) webSocketURL(address string) (string, error) {
+	u, err := url.Parse(dialer.proxyURL)
+	if err != nil {
+		return "", ErrWebSocket.Wrap(err)
+	}
+	query := u.Query()
+	query.Set("address", address)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// webSocketAddr is the address of a WebSocket connection.
+type webSocketAddr string
+
+// Network implements net.Addr.
+func (addr webSocketAddr) Network() string { return "websocket" }
+
+// String implements net.Addr.
+func (addr webSocketAddr) String() string { return string(addr) }
+
+var _ net.Addr = webSocketAddr("")
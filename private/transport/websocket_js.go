@@ -0,0 +1,233 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+//go:build js && wasm
+
+package transport
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall/js"
+	"time"
+)
+
+// DialContext dials address through the WebSocket proxy.
+func (dialer *webSocketDialer) DialContext(ctx context.Context, network, address string) (_ net.Conn, err error) {
+	wsURL, err := dialer.webSocketURL(address)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrWebSocket.New("%v", r)
+		}
+	}()
+
+	ws := js.Global().Get("WebSocket").New(wsURL)
+	ws.Set("binaryType", "arraybuffer")
+
+	conn := &webSocketConn{
+		ws:      ws,
+		address: address,
+		opened:  make(chan struct{}),
+		notify:  make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+	}
+	conn.listen()
+
+	select {
+	case <-conn.opened:
+		return conn, nil
+	case <-conn.closed:
+		conn.release()
+		return nil, ErrWebSocket.New("connecting to %q failed", address)
+	case <-ctx.Done():
+		_ = conn.Close()
+		return nil, ctx.Err()
+	}
+}
+
+// webSocketConn implements net.Conn using the WebSocket API of the browser.
+type webSocketConn struct {
+	ws      js.Value
+	address string
+	funcs   []js.Func
+
+	opened chan struct{}
+	notify chan struct{}
+	closed chan struct{}
+
+	mu            sync.Mutex
+	received      [][]byte
+	pending       []byte
+	closeOnce     sync.Once
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+// listen registers the event handlers of the WebSocket. The handlers must not
+// block, so received messages are queued.
+func (conn *webSocketConn) listen() {
+	handle := func(event string, fn func(args []js.Value)) {
+		f := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			fn(args)
+			return nil
+		})
+		conn.funcs = append(conn.funcs, f)
+		conn.ws.Set(event, f)
+	}
+
+	handle("onopen", func(args []js.Value) {
+		close(conn.opened)
+	})
+	handle("onmessage", func(args []js.Value) {
+		array := js.Global().Get("Uint8Array").New(args[0].Get("data"))
+		data := make([]byte, array.Length())
+		js.CopyBytesToGo(data, array)
+
+		conn.mu.Lock()
+		conn.received = append(conn.received, data)
+		conn.mu.Unlock()
+
+		select {
+		case conn.notify <- struct{}{}:
+		default:
+		}
+	})
+	handle("onclose", func(args []js.Value) {
+		conn.closeOnce.Do(func() { close(conn.closed) })
+	})
+	handle("onerror", func(args []js.Value) {
+		conn.closeOnce.Do(func() { close(conn.closed) })
+	})
+}
+
+// release unregisters the event handlers.
+func (conn *webSocketConn) release() {
+	for _, event := range []string{"onopen", "onmessage", "onclose", "onerror"} {
+		conn.ws.Set(event, js.Null())
+	}
+	for _, f := range conn.funcs {
+		f.Release()
+	}
+	conn.funcs = nil
+}
+
+// Read implements net.Conn.
+func (conn *webSocketConn) Read(p []byte) (n int, err error) {
+	for {
+		conn.mu.Lock()
+		if len(conn.pending) == 0 && len(conn.received) > 0 {
+			conn.pending = conn.received[0]
+			conn.received = conn.received[1:]
+		}
+		if len(conn.pending) > 0 {
+			n = copy(p, conn.pending)
+			conn.pending = conn.pending[n:]
+			conn.mu.Unlock()
+			return n, nil
+		}
+		deadline := conn.readDeadline
+		conn.mu.Unlock()
+
+		if err := conn.wait(deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// wait waits until a message is received, the connection is closed or the
+// deadline passes.
+func (conn *webSocketConn) wait(deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return os.ErrDeadlineExceeded
+		}
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-conn.notify:
+		return nil
+	case <-conn.closed:
+		// deliver messages that were received before closing.
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		if len(conn.received) == 0 {
+			return io.EOF
+		}
+		return nil
+	case <-timeout:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+// Write implements net.Conn. The browser buffers the sent data, so Write
+// doesn't block.
+func (conn *webSocketConn) Write(p []byte) (n int, err error) {
+	conn.mu.Lock()
+	deadline := conn.writeDeadline
+	conn.mu.Unlock()
+
+	if !deadline.IsZero() && !time.Now().Before(deadline) {
+		return 0, os.ErrDeadlineExceeded
+	}
+
+	select {
+	case <-conn.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+
+	array := js.Global().Get("Uint8Array").New(len(p))
+	js.CopyBytesToJS(array, p)
+	conn.ws.Call("send", array)
+	return len(p), nil
+}
+
+// Close implements net.Conn.
+func (conn *webSocketConn) Close() error {
+	conn.ws.Call("close")
+	conn.closeOnce.Do(func() { close(conn.closed) })
+	conn.release()
+	return nil
+}
+
+// LocalAddr implements net.Conn.
+func (conn *webSocketConn) LocalAddr() net.Addr { return webSocketAddr("browser") }
+
+// RemoteAddr implements net.Conn.
+func (conn *webSocketConn) RemoteAddr() net.Addr { return webSocketAddr(conn.address) }
+
+// SetDeadline implements net.Conn.
+func (conn *webSocketConn) SetDeadline(t time.Time) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.readDeadline, conn.writeDeadline = t, t
+	return nil
+}
+
+// SetReadDeadline implements net.Conn.
+func (conn *webSocketConn) SetReadDeadline(t time.Time) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.readDeadline = t
+	return nil
+}
+
+// SetWriteDeadline implements net.Conn.
+func (conn *webSocketConn) SetWriteDeadline(t time.Time) error {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	conn.writeDeadline = t
+	return nil
+}
@@ -0,0 +1,16 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+//go:build !(js && wasm)
+
+package transport
+
+import (
+	"context"
+	"net"
+)
+
+// DialContext dials address through the WebSocket proxy.
+func (dialer *webSocketDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return nil, ErrWebSocket.New("only supported on js/wasm")
+}
@@ -12,8 +12,10 @@ import (
 	"storj.io/common/memory"
 	"storj.io/common/rpc"
 	"storj.io/common/storj"
+	"storj.io/uplink/private/diskcache"
 	"storj.io/uplink/private/ecclient"
 	"storj.io/uplink/private/metaclient"
+	"storj.io/uplink/private/oteltrace"
 	"storj.io/uplink/private/storage/streams"
 	"storj.io/uplink/private/testuplink"
 	"storj.io/uplink/private/version"
@@ -36,6 +38,9 @@ type Project struct {
 	segmentSize                   int64
 	encryptionParameters          storj.EncryptionParameters
 	concurrentSegmentUploadConfig *testuplink.ConcurrentSegmentUploadsConfig
+	cache                         *diskcache.Cache
+	recentStats                   *statCache
+	inflight                      inflight
 
 	tracker leak.Ref
 }
@@ -60,6 +65,10 @@ func (config Config) OpenProject(ctx context.Context, access *Access) (project *
 		config.DialTimeout = defaultDialTimeout
 	}
 
+	if err := config.LongTail.validate(); err != nil {
+		return nil, err
+	}
+
 	if err := config.validateUserAgent(ctx); err != nil {
 		return nil, packageError.New("invalid user agent: %w", err)
 	}
@@ -82,11 +91,16 @@ func (config Config) OpenProject(ctx context.Context, access *Access) (project *
 	// stripe size of the default redundancy scheme on the satellite.
 	encBlockSize := 29 * 256 * memory.B.Int32()
 
+	cipherSuite, err := config.ContentCipher.cipherSuite()
+	if err != nil {
+		return nil, err
+	}
+
 	encryptionParameters := storj.EncryptionParameters{
 		// N.B.: This is the ciphersuite we use for encrypting content keys,
 		// which should absolutely be encrypted, even if the access grant
 		// says EncNull.
-		CipherSuite: storj.EncAESGCM,
+		CipherSuite: cipherSuite,
 		BlockSize:   encBlockSize,
 	}
 
@@ -109,7 +123,19 @@ func (config Config) OpenProject(ctx context.Context, access *Access) (project *
 		}
 	}
 
-	ec := ecclient.New(storagenodeDialer, 0)
+	ec := ecclient.New(storagenodeDialer, 0).WithLongTail(ecclient.LongTail{
+		SuccessThresholdMultiplier: config.LongTail.SuccessThresholdMultiplier,
+		MinimumSuccesses:           config.LongTail.MinimumSuccesses,
+		DownloadExtraPieces:        config.LongTail.DownloadExtraPieces,
+	})
+
+	var cache *diskcache.Cache
+	if config.PlaintextCacheDir != "" {
+		cache, err = diskcache.New(config.PlaintextCacheDir, config.CacheMaxSize, config.CacheTTL)
+		if err != nil {
+			return nil, packageError.Wrap(err)
+		}
+	}
 
 	tracker := leak.FromContext(ctx)
 	if tracker == (leak.Ref{}) { // TODO: handle this check better
@@ -125,6 +151,8 @@ func (config Config) OpenProject(ctx context.Context, access *Access) (project *
 		segmentSize:                   segmentsSize,
 		encryptionParameters:          encryptionParameters,
 		concurrentSegmentUploadConfig: testuplink.GetConcurrentSegmentUploadsConfig(ctx),
+		cache:                         cache,
+		recentStats:                   newStatCache(config.StatReuseWindow),
 
 		tracker: tracker,
 	}, nil
@@ -146,6 +174,12 @@ func (project *Project) Close() (err error) {
 }
 
 func (project *Project) getStreamsStore(ctx context.Context) (_ *streams.Store, err error) {
+	return project.getStreamsStoreWith(ctx, project.segmentSize, project.encryptionParameters)
+}
+
+// getStreamsStoreWith returns a streams store, which uploads segments of
+// segmentSize bytes encrypted with encryptionParameters.
+func (project *Project) getStreamsStoreWith(ctx context.Context, segmentSize int64, encryptionParameters storj.EncryptionParameters) (_ *streams.Store, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	metainfoClient, err := project.dialMetainfoClient(ctx)
@@ -166,9 +200,9 @@ func (project *Project) getStreamsStore(ctx context.Context) (_ *streams.Store,
 	streamStore, err := streams.NewStreamStore(
 		metainfoClient,
 		project.ec,
-		project.segmentSize,
+		segmentSize,
 		project.access.encAccess.Store,
-		project.encryptionParameters,
+		encryptionParameters,
 		maxInlineSize,
 		longTailMargin)
 	if err != nil {
@@ -192,6 +226,10 @@ func (project *Project) dialMetainfoDB(ctx context.Context) (_ *metaclient.DB, e
 func (project *Project) dialMetainfoClient(ctx context.Context) (_ *metaclient.Client, err error) {
 	defer mon.Task()(&ctx)(&err)
 
+	// every operation talks to the satellite, so this is where the operation
+	// trace starts being mirrored.
+	oteltrace.Attach(ctx, project.config.TracerProvider)
+
 	metainfoClient, err := metaclient.DialNodeURL(ctx,
 		project.satelliteDialer,
 		project.access.satelliteURL.String(),
@@ -200,6 +238,7 @@ func (project *Project) dialMetainfoClient(ctx context.Context) (_ *metaclient.C
 	if err != nil {
 		return nil, packageError.Wrap(err)
 	}
+	metainfoClient.SetTimeout(project.config.MetainfoTimeout)
 
 	return metainfoClient, nil
 }
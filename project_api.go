@@ -0,0 +1,49 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+)
+
+// ProjectAPI is the set of methods of Project. Applications can depend on it
+// instead of *Project, so the project can be replaced in tests, e.g. with the
+// in-memory project of storj.io/uplink/uplinktest.
+type ProjectAPI interface {
+	StatBucket(ctx context.Context, bucket string) (*Bucket, error)
+	CreateBucket(ctx context.Context, bucket string) (*Bucket, error)
+	EnsureBucket(ctx context.Context, bucket string) (*Bucket, error)
+	DeleteBucket(ctx context.Context, bucket string) (*Bucket, error)
+	DeleteBucketWithObjects(ctx context.Context, bucket string) (*Bucket, error)
+	ListBuckets(ctx context.Context, options *ListBucketsOptions) *BucketIterator
+	ExportBucketMetadata(ctx context.Context, bucket string, options *ExportBucketOptions) (*BucketExport, error)
+	ImportBucketMetadata(ctx context.Context, export *BucketExport, options *ImportBucketOptions) (*Bucket, error)
+
+	StatObject(ctx context.Context, bucket, key string) (*Object, error)
+	StatObjectWithOptions(ctx context.Context, bucket, key string, options *StatObjectOptions) (*Object, error)
+	StatObjects(ctx context.Context, bucket string, keys []string) ([]StatObjectResult, error)
+	UploadObject(ctx context.Context, bucket, key string, options *UploadOptions) (*Upload, error)
+	DownloadObject(ctx context.Context, bucket, key string, options *DownloadOptions) (*Download, error)
+	DownloadObjectWithInfo(ctx context.Context, bucket string, object *Object, options *DownloadOptions) (*Download, error)
+	DeleteObject(ctx context.Context, bucket, key string) (*Object, error)
+	DeletePrefix(ctx context.Context, bucket, prefix string, options *DeletePrefixOptions) (*DeletePrefixResult, error)
+	UpdateObjectMetadata(ctx context.Context, bucket, key string, newMetadata CustomMetadata, options *UploadObjectMetadataOptions) error
+	CopyObject(ctx context.Context, oldBucket, oldKey, newBucket, newKey string, options *CopyObjectOptions) (*Object, error)
+	MoveObject(ctx context.Context, oldBucket, oldKey, newBucket, newKey string, options *MoveObjectOptions) error
+	ListObjects(ctx context.Context, bucket string, options *ListObjectsOptions) *ObjectIterator
+
+	BeginUpload(ctx context.Context, bucket, key string, options *UploadOptions) (UploadInfo, error)
+	UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber uint32) (*PartUpload, error)
+	CommitUpload(ctx context.Context, bucket, key, uploadID string, options *CommitUploadOptions) (*Object, error)
+	AbortUpload(ctx context.Context, bucket, key, uploadID string) error
+	ListUploads(ctx context.Context, bucket string, options *ListUploadsOptions) *UploadIterator
+	ListUploadParts(ctx context.Context, bucket, key, uploadID string, options *ListUploadPartsOptions) *PartIterator
+
+	Capabilities(ctx context.Context) (*Capabilities, error)
+	RevokeAccess(ctx context.Context, access *Access) error
+	Close() error
+	Shutdown(ctx context.Context) error
+}
+
+var _ ProjectAPI = (*Project)(nil)
@@ -0,0 +1,119 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"strings"
+)
+
+// PublicPrefix provides read-only access to the objects shared under a
+// prefix, e.g. for publishing static content.
+type PublicPrefix struct {
+	project *Project
+	bucket  string
+	prefix  string
+}
+
+// OpenPublicPrefix opens read-only access to the objects under prefix in
+// bucket. The access grant is restricted to listing and downloading that
+// prefix before anything else is done with it, so the returned PublicPrefix
+// holds no credential that allows writes or deletes.
+//
+// If not empty, prefix must end with slash.
+func OpenPublicPrefix(ctx context.Context, access *Access, bucket, prefix string) (*PublicPrefix, error) {
+	return (Config{}).OpenPublicPrefix(ctx, access, bucket, prefix)
+}
+
+// OpenPublicPrefix opens read-only access to the objects under prefix in
+// bucket. The access grant is restricted to listing and downloading that
+// prefix before anything else is done with it, so the returned PublicPrefix
+// holds no credential that allows writes or deletes.
+//
+// If not empty, prefix must end with slash.
+func (config Config) OpenPublicPrefix(ctx context.Context, access *Access, bucket, prefix string) (_ *PublicPrefix, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if access == nil {
+		return nil, packageError.New("access grant is nil")
+	}
+	if bucket == "" {
+		return nil, errwrapf("%w (%q)", ErrBucketNameInvalid, bucket)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return nil, packageError.New("prefix %q doesn't end with slash", prefix)
+	}
+
+	restricted, err := access.Share(ReadOnlyPermission(), SharePrefix{
+		Bucket: bucket,
+		Prefix: prefix,
+	})
+	if err != nil {
+		return nil, packageError.Wrap(err)
+	}
+
+	project, err := config.OpenProject(ctx, restricted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicPrefix{
+		project: project,
+		bucket:  bucket,
+		prefix:  prefix,
+	}, nil
+}
+
+// Bucket returns the name of the shared bucket.
+func (public *PublicPrefix) Bucket() string { return public.bucket }
+
+// Prefix returns the shared prefix.
+func (public *PublicPrefix) Prefix() string { return public.prefix }
+
+// ListObjects returns an iterator over the shared objects. Keys are relative
+// to the bucket. When options.Prefix is empty, the shared prefix is listed.
+func (public *PublicPrefix) ListObjects(ctx context.Context, options *ListObjectsOptions) *ObjectIterator {
+	opts := ListObjectsOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = public.prefix
+	}
+	if !strings.HasPrefix(opts.Prefix, public.prefix) {
+		return &ObjectIterator{
+			err: errwrapf("%w: prefix %q is not shared", ErrPermissionDenied, opts.Prefix),
+		}
+	}
+	return public.project.ListObjects(ctx, public.bucket, &opts)
+}
+
+// StatObject returns information about a shared object.
+func (public *PublicPrefix) StatObject(ctx context.Context, key string) (*Object, error) {
+	if err := public.checkKey(key); err != nil {
+		return nil, err
+	}
+	return public.project.StatObject(ctx, public.bucket, key)
+}
+
+// DownloadObject starts a download from a shared object.
+func (public *PublicPrefix) DownloadObject(ctx context.Context, key string, options *DownloadOptions) (*Download, error) {
+	if err := public.checkKey(key); err != nil {
+		return nil, err
+	}
+	return public.project.DownloadObject(ctx, public.bucket, key, options)
+}
+
+// Close closes the underlying project.
+func (public *PublicPrefix) Close() error {
+	return public.project.Close()
+}
+
+// checkKey returns an error when key is not under the shared prefix.
+func (public *PublicPrefix) checkKey(key string) error {
+	if !strings.HasPrefix(key, public.prefix) {
+		return errwrapf("%w: key %q is not shared", ErrPermissionDenied, key)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"io"
+	_ "unsafe" // for go:linkname
+
+	"github.com/zeebo/errs"
+)
+
+// reuploadObject downloads object from sourceBucket of source and uploads
+// it to key in bucket of target. Custom metadata, expiration and
+// compression of object are preserved. object must contain the custom
+// metadata. When tee is not nil, the downloaded content is also written
+// to it.
+//
+// The download returns the content decompressed, so the compression is
+// removed from the custom metadata and the content is compressed again by
+// the upload.
+//
+// NB: this is used with linkname in internal/expose.
+// It needs to be updated when this is updated.
+//
+//go:linkname reuploadObject
+func reuploadObject(ctx context.Context, source ProjectAPI, sourceBucket string, object *Object, target ProjectAPI, bucket, key string, tee io.Writer) (_ *Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	download, err := source.DownloadObject(ctx, sourceBucket, object.Key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, download.Close()) }()
+
+	custom := object.Custom.Clone()
+	compression := objectCompression(custom)
+	if compression != CompressionNone {
+		delete(custom, CompressionMetadataKey)
+	}
+
+	options := &UploadOptions{
+		Expires:     object.System.Expires,
+		Compression: compression,
+	}
+	if compression == CompressionNone {
+		options.ContentLength = object.System.ContentLength
+	}
+
+	upload, err := target.UploadObject(ctx, bucket, key, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = download
+	if tee != nil {
+		r = io.TeeReader(download, tee)
+	}
+	if _, err := io.Copy(upload, r); err != nil {
+		return nil, errs.Combine(err, upload.Abort())
+	}
+	if err := upload.SetCustomMetadata(ctx, custom); err != nil {
+		return nil, errs.Combine(err, upload.Abort())
+	}
+	if err := upload.Commit(); err != nil {
+		return nil, err
+	}
+	return upload.Info(), nil
+}
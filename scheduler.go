@@ -0,0 +1,62 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"storj.io/uplink/private/eestream/scheduler"
+	"storj.io/uplink/private/storage/streams/segmentupload"
+)
+
+// Scheduler limits the uploads of all projects opened with a Config that
+// shares it, e.g. in a service that opens a project per tenant.
+//
+// The limits apply in addition to the limits every upload has on its own.
+// Only uploads are limited, i.e. UploadObject and UploadPart, and only when
+// they use the default concurrent segment upload code path. Downloads and
+// uploads with concurrent segment uploads disabled aren't limited by a
+// Scheduler.
+type Scheduler struct {
+	sched *scheduler.Scheduler
+}
+
+// SchedulerOptions contains the limits of a Scheduler.
+type SchedulerOptions struct {
+	// MaxConcurrentPieces is the maximum number of piece uploads in
+	// progress. It must be positive.
+	MaxConcurrentPieces int
+
+	// MaxConcurrentSegments is the maximum number of segments uploaded at
+	// the same time. Every segment in progress is held in memory, so it also
+	// limits the memory used by uploads to about MaxConcurrentSegments times
+	// the segment size.
+	// Zero means no limit.
+	MaxConcurrentSegments int
+}
+
+// NewScheduler returns a new scheduler with the given limits.
+func NewScheduler(options SchedulerOptions) (*Scheduler, error) {
+	if options.MaxConcurrentPieces <= 0 {
+		return nil, packageError.New("invalid maximum concurrent pieces %d", options.MaxConcurrentPieces)
+	}
+	if options.MaxConcurrentSegments < 0 {
+		return nil, packageError.New("invalid maximum concurrent segments %d", options.MaxConcurrentSegments)
+	}
+
+	return &Scheduler{
+		sched: scheduler.New(scheduler.Options{
+			MaximumConcurrent:        options.MaxConcurrentPieces,
+			MaximumConcurrentHandles: options.MaxConcurrentSegments,
+		}),
+	}, nil
+}
+
+// uploadScheduler returns the scheduler for a new upload, which applies the
+// limits of the upload and of Config.Scheduler.
+func (project *Project) uploadScheduler() segmentupload.Scheduler {
+	sched := scheduler.New(project.concurrentSegmentUploadConfig.SchedulerOptions)
+	if project.config.Scheduler == nil {
+		return sched
+	}
+	return scheduler.Chain{sched, project.config.Scheduler.sched}
+}
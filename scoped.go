@@ -0,0 +1,158 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"strings"
+
+	"storj.io/common/paths"
+	"storj.io/common/storj"
+)
+
+// ScopedProject provides access to the objects of the single bucket and
+// prefix an access grant was shared for, so the bucket doesn't need to be
+// repeated for every call. Object keys are relative to the bucket.
+type ScopedProject struct {
+	project *Project
+	bucket  string
+	prefix  string
+}
+
+// OpenScopedProject opens a project scoped to the bucket and prefix that
+// access was restricted to with Access.Share. It fails when access allows
+// more than a single bucket and prefix.
+func OpenScopedProject(ctx context.Context, access *Access) (*ScopedProject, error) {
+	return (Config{}).OpenScopedProject(ctx, access)
+}
+
+// OpenScopedProject opens a project scoped to the bucket and prefix that
+// access was restricted to with Access.Share. It fails when access allows
+// more than a single bucket and prefix.
+func (config Config) OpenScopedProject(ctx context.Context, access *Access) (_ *ScopedProject, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if access == nil {
+		return nil, packageError.New("access grant is nil")
+	}
+
+	bucket, prefix, err := access.scope()
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := config.OpenProject(ctx, access)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ScopedProject{
+		project: project,
+		bucket:  bucket,
+		prefix:  prefix,
+	}, nil
+}
+
+// scope returns the single bucket and prefix the access grant is restricted
+// to. Sharing a prefix only keeps the encryption information for it, so the
+// scope is the only entry in the encryption store.
+func (access *Access) scope() (bucket, prefix string, err error) {
+	if access.encAccess.Store.GetDefaultKey() != nil {
+		return "", "", packageError.New("access grant is not restricted to a bucket")
+	}
+
+	var found int
+	err = access.encAccess.Store.Iterate(func(b string, unenc paths.Unencrypted, _ paths.Encrypted, _ storj.Key) error {
+		found++
+		bucket, prefix = b, unenc.Raw()
+		return nil
+	})
+	if err != nil {
+		return "", "", packageError.Wrap(err)
+	}
+
+	switch {
+	case found == 0:
+		return "", "", packageError.New("access grant is not restricted to a bucket")
+	case found > 1:
+		return "", "", packageError.New("access grant is restricted to %d prefixes, expected one", found)
+	}
+
+	if prefix != "" {
+		prefix += "/"
+	}
+	return bucket, prefix, nil
+}
+
+// Bucket returns the name of the bucket.
+func (scoped *ScopedProject) Bucket() string { return scoped.bucket }
+
+// Prefix returns the prefix of the keys that can be accessed.
+func (scoped *ScopedProject) Prefix() string { return scoped.prefix }
+
+// Project returns the underlying project.
+func (scoped *ScopedProject) Project() *Project { return scoped.project }
+
+// ListObjects returns an iterator over the objects. When options.Prefix is
+// empty, all accessible objects are listed.
+func (scoped *ScopedProject) ListObjects(ctx context.Context, options *ListObjectsOptions) *ObjectIterator {
+	opts := ListObjectsOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = scoped.prefix
+	}
+	if !strings.HasPrefix(opts.Prefix, scoped.prefix) {
+		return &ObjectIterator{
+			err: errwrapf("%w: prefix %q is outside of %q", ErrPermissionDenied, opts.Prefix, scoped.prefix),
+		}
+	}
+	return scoped.project.ListObjects(ctx, scoped.bucket, &opts)
+}
+
+// StatObject returns information about an object.
+func (scoped *ScopedProject) StatObject(ctx context.Context, key string) (*Object, error) {
+	if err := scoped.checkKey(key); err != nil {
+		return nil, err
+	}
+	return scoped.project.StatObject(ctx, scoped.bucket, key)
+}
+
+// UploadObject starts an upload to an object.
+func (scoped *ScopedProject) UploadObject(ctx context.Context, key string, options *UploadOptions) (*Upload, error) {
+	if err := scoped.checkKey(key); err != nil {
+		return nil, err
+	}
+	return scoped.project.UploadObject(ctx, scoped.bucket, key, options)
+}
+
+// DownloadObject starts a download from an object.
+func (scoped *ScopedProject) DownloadObject(ctx context.Context, key string, options *DownloadOptions) (*Download, error) {
+	if err := scoped.checkKey(key); err != nil {
+		return nil, err
+	}
+	return scoped.project.DownloadObject(ctx, scoped.bucket, key, options)
+}
+
+// DeleteObject deletes an object.
+func (scoped *ScopedProject) DeleteObject(ctx context.Context, key string) (*Object, error) {
+	if err := scoped.checkKey(key); err != nil {
+		return nil, err
+	}
+	return scoped.project.DeleteObject(ctx, scoped.bucket, key)
+}
+
+// Close closes the underlying project.
+func (scoped *ScopedProject) Close() error {
+	return scoped.project.Close()
+}
+
+// checkKey returns an error when key is outside of the prefix.
+func (scoped *ScopedProject) checkKey(key string) error {
+	if !strings.HasPrefix(key, scoped.prefix) {
+		return errwrapf("%w: key %q is outside of %q", ErrPermissionDenied, key, scoped.prefix)
+	}
+	return nil
+}
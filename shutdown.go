@@ -0,0 +1,126 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrProjectShutdown is returned when an upload or download is started after
+// Project.Shutdown was called.
+var ErrProjectShutdown = errors.New("project shut down")
+
+// inflight tracks the uploads and downloads of a project, which haven't
+// been committed, aborted or closed yet, so Shutdown can abort them.
+type inflight struct {
+	mu     sync.Mutex
+	next   int
+	ops    map[int]inflightOp
+	closed bool
+}
+
+// inflightOp is an operation tracked by inflight.
+type inflightOp struct {
+	// cancel cancels the context of the operation, which unblocks reads and
+	// writes in progress.
+	cancel func()
+	// abort aborts or closes the operation and releases its resources.
+	abort func() error
+}
+
+// add starts tracking op. The returned func stops tracking it and must be
+// called when the operation finishes. It fails with ErrProjectShutdown after
+// takeAll was called, in which case the caller must release op itself.
+func (in *inflight) add(op inflightOp) (done func(), err error) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.closed {
+		return nil, packageError.Wrap(ErrProjectShutdown)
+	}
+	if in.ops == nil {
+		in.ops = make(map[int]inflightOp)
+	}
+	id := in.next
+	in.next++
+	in.ops[id] = op
+
+	return func() {
+		in.mu.Lock()
+		defer in.mu.Unlock()
+		delete(in.ops, id)
+	}, nil
+}
+
+// err returns ErrProjectShutdown after takeAll was called, so operations can
+// fail before acquiring any resources.
+func (in *inflight) err() error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	if in.closed {
+		return packageError.Wrap(ErrProjectShutdown)
+	}
+	return nil
+}
+
+// takeAll stops tracking all operations and returns them. Operations added
+// afterwards are rejected.
+func (in *inflight) takeAll() []inflightOp {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	ops := make([]inflightOp, 0, len(in.ops))
+	for _, op := range in.ops {
+		ops = append(ops, op)
+	}
+	in.ops = nil
+	in.closed = true
+	return ops
+}
+
+// Shutdown aborts all uploads and closes all downloads of the project that
+// are still in progress, waits for them to release their resources and
+// closes the project.
+//
+// Reads and writes in progress fail and later calls to Commit return
+// ErrUploadDone. Uploads and downloads started after Shutdown fail with
+// ErrProjectShutdown. When ctx is done before the operations finished
+// cleaning up, Shutdown closes the project without waiting further and
+// returns the error of ctx. Errors of the aborted operations aren't returned.
+func (project *Project) Shutdown(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ops := project.inflight.takeAll()
+	for _, op := range ops {
+		op.cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for _, op := range ops {
+			wg.Add(1)
+			go func(op inflightOp) {
+				defer wg.Done()
+				_ = op.abort()
+			}(op)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return errs.Combine(err, project.Close())
+}
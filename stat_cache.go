@@ -0,0 +1,122 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/uplink/private/metaclient"
+)
+
+// maxRecentStats limits the number of objects remembered by statCache.
+const maxRecentStats = 64
+
+// statCache remembers the objects returned by StatObject for a short time,
+// so a download that follows can skip requesting the same information from
+// the satellite again.
+type statCache struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	entries map[statCacheKey]statCacheEntry
+}
+
+type statCacheKey struct {
+	bucket, key string
+}
+
+type statCacheEntry struct {
+	object *metaclient.Object
+	added  time.Time
+}
+
+// newStatCache returns a statCache that keeps objects for window. It returns
+// nil when window is not positive.
+func newStatCache(window time.Duration) *statCache {
+	if window <= 0 {
+		return nil
+	}
+	return &statCache{
+		window:  window,
+		entries: make(map[statCacheKey]statCacheEntry),
+	}
+}
+
+// add remembers object. When the cache is full, expired entries are removed,
+// and the object is dropped if that doesn't make room.
+func (cache *statCache) add(bucket, key string, object *metaclient.Object) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := time.Now()
+	if len(cache.entries) >= maxRecentStats {
+		for k, entry := range cache.entries {
+			if now.Sub(entry.added) >= cache.window {
+				delete(cache.entries, k)
+			}
+		}
+		if len(cache.entries) >= maxRecentStats {
+			return
+		}
+	}
+	cache.entries[statCacheKey{bucket, key}] = statCacheEntry{object: object, added: now}
+}
+
+// take returns and forgets the object that was added for bucket and key
+// within the window.
+func (cache *statCache) take(bucket, key string) *metaclient.Object {
+	if cache == nil {
+		return nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	k := statCacheKey{bucket, key}
+	entry, ok := cache.entries[k]
+	if !ok {
+		return nil
+	}
+	delete(cache.entries, k)
+	if time.Since(entry.added) >= cache.window {
+		return nil
+	}
+	return entry.object
+}
+
+// forget removes the object for bucket and key, e.g. because it was
+// modified.
+func (cache *statCache) forget(bucket, key string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	delete(cache.entries, statCacheKey{bucket, key})
+}
+
+// forgetPrefix removes the objects in bucket whose key starts with prefix,
+// e.g. because they were deleted.
+func (cache *statCache) forgetPrefix(bucket, prefix string) {
+	if cache == nil {
+		return
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for k := range cache.entries {
+		if k.bucket == bucket && strings.HasPrefix(k.key, prefix) {
+			delete(cache.entries, k)
+		}
+	}
+}
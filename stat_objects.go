@@ -0,0 +1,99 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/uplink/private/metaclient"
+)
+
+// statObjectsBatchSize is the number of objects requested in a single batch
+// by StatObjects.
+const statObjectsBatchSize = 100
+
+// StatObjectResult is the result of StatObjects for a single key.
+type StatObjectResult struct {
+	Key string
+	// Object is the information about the object. It's nil when Err is set.
+	Object *Object
+	// Err is the error of the key, e.g. ErrObjectNotFound.
+	Err error
+}
+
+// StatObjects returns information about the objects at the specified keys.
+// The objects are requested in batches, so many keys can be resolved with a
+// few requests.
+//
+// The results are in the same order as keys and hold the error of every key
+// separately. The returned error is only set when the objects couldn't be
+// requested at all.
+func (project *Project) StatObjects(ctx context.Context, bucket string, keys []string) (results []StatObjectResult, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if bucket == "" {
+		return nil, errwrapf("%w (%q)", ErrBucketNameInvalid, bucket)
+	}
+
+	db, err := project.dialMetainfoDB(ctx)
+	if err != nil {
+		return nil, convertKnownErrors(err, bucket, "")
+	}
+	defer func() { err = errs.Combine(err, db.Close()) }()
+
+	results = make([]StatObjectResult, 0, len(keys))
+	for len(keys) > 0 {
+		batch := keys
+		if len(batch) > statObjectsBatchSize {
+			batch = batch[:statObjectsBatchSize]
+		}
+		keys = keys[len(batch):]
+
+		results = project.statBatch(ctx, db, bucket, batch, results)
+		if err := ctx.Err(); err != nil {
+			return nil, packageError.Wrap(err)
+		}
+	}
+	return results, nil
+}
+
+// statBatch requests keys with a single request. The satellite fails the
+// whole request when any of the keys fails, so a failed request is split in
+// halves until the failing keys are found. The other keys are still
+// requested in batches.
+func (project *Project) statBatch(ctx context.Context, db *metaclient.DB, bucket string, keys []string, results []StatObjectResult) []StatObjectResult {
+	objects, err := db.GetObjects(ctx, bucket, keys)
+	if err != nil {
+		if len(keys) == 1 || ctx.Err() != nil {
+			for _, key := range keys {
+				results = append(results, StatObjectResult{
+					Key: key,
+					Err: convertKnownErrors(err, bucket, key),
+				})
+			}
+			return results
+		}
+
+		half := len(keys) / 2
+		results = project.statBatch(ctx, db, bucket, keys[:half], results)
+		return project.statBatch(ctx, db, bucket, keys[half:], results)
+	}
+
+	for i, object := range objects {
+		if object.Err != nil {
+			results = append(results, StatObjectResult{
+				Key: keys[i],
+				Err: convertKnownErrors(object.Err, bucket, keys[i]),
+			})
+			continue
+		}
+		results = append(results, StatObjectResult{
+			Key:    keys[i],
+			Object: convertObject(&object.Object),
+		})
+	}
+	return results
+}
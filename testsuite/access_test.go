@@ -990,3 +990,42 @@ func TestMultipleAccessRestrict(t *testing.T) {
 		assert.NoError(t, objects.Err())
 	})
 }
+
+func TestShareWriteOnlyDropbox(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+		err := planet.Uplinks[0].CreateBucket(ctx, planet.Satellites[0], "testbucket")
+		require.NoError(t, err)
+
+		sharedAccess, err := uplink.WriteOnlyDropbox("testbucket", "inbox/").Share(access)
+		require.NoError(t, err)
+
+		project, err := uplink.OpenProject(ctx, sharedAccess)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		upload, err := project.UploadObject(ctx, "testbucket", "inbox/object", nil)
+		require.NoError(t, err)
+		_, err = upload.Write(testrand.Bytes(5 * memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+
+		_, err = project.UploadObject(ctx, "testbucket", "outbox/object", nil)
+		require.ErrorIs(t, err, uplink.ErrPermissionDenied)
+
+		_, err = project.DownloadObject(ctx, "testbucket", "inbox/object", nil)
+		require.ErrorIs(t, err, uplink.ErrPermissionDenied)
+
+		objects := project.ListObjects(ctx, "testbucket", &uplink.ListObjectsOptions{Prefix: "inbox/"})
+		require.False(t, objects.Next())
+		require.ErrorIs(t, objects.Err(), uplink.ErrPermissionDenied)
+
+		_, err = project.DeleteObject(ctx, "testbucket", "inbox/object")
+		require.Error(t, err)
+	})
+}
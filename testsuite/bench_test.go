@@ -0,0 +1,179 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func BenchmarkUpload(b *testing.B) {
+	sizes := []memory.Size{
+		1 * memory.KiB,
+		256 * memory.KiB,
+		64 * memory.MiB,
+	}
+
+	testplanet.Bench(b, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+	}, func(b *testing.B, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(b, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "bench")
+		require.NoError(b, err)
+
+		for _, size := range sizes {
+			data := testrand.Bytes(size)
+
+			b.Run(size.String(), func(b *testing.B) {
+				b.SetBytes(size.Int64())
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					require.NoError(b, benchUpload(ctx, project, "bench", "object", data))
+				}
+			})
+		}
+	})
+}
+
+func BenchmarkDownload(b *testing.B) {
+	sizes := []memory.Size{
+		1 * memory.KiB,
+		256 * memory.KiB,
+		64 * memory.MiB,
+	}
+
+	testplanet.Bench(b, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+	}, func(b *testing.B, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(b, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "bench")
+		require.NoError(b, err)
+
+		for _, size := range sizes {
+			key := size.String()
+			require.NoError(b, benchUpload(ctx, project, "bench", key, testrand.Bytes(size)))
+
+			b.Run(size.String(), func(b *testing.B) {
+				b.SetBytes(size.Int64())
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					download, err := project.DownloadObject(ctx, "bench", key, nil)
+					require.NoError(b, err)
+
+					_, err = io.Copy(io.Discard, download)
+					require.NoError(b, err)
+					require.NoError(b, download.Close())
+				}
+			})
+		}
+	})
+}
+
+func BenchmarkParallelUpload(b *testing.B) {
+	const concurrency = 8
+
+	testplanet.Bench(b, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+	}, func(b *testing.B, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(b, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "bench")
+		require.NoError(b, err)
+
+		data := testrand.Bytes(5 * memory.MiB)
+
+		b.SetBytes(concurrency * int64(len(data)))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			var group errgroup.Group
+			for p := 0; p < concurrency; p++ {
+				key := strconv.Itoa(p)
+				group.Go(func() error {
+					return benchUpload(ctx, project, "bench", key, data)
+				})
+			}
+			require.NoError(b, group.Wait())
+		}
+	})
+}
+
+func BenchmarkListObjects(b *testing.B) {
+	const (
+		objectCount = 100000
+		concurrency = 32
+	)
+
+	testplanet.Bench(b, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 0, UplinkCount: 1,
+	}, func(b *testing.B, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(b, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "bench")
+		require.NoError(b, err)
+
+		// objects are small enough to be stored inline
+		var group errgroup.Group
+		group.SetLimit(concurrency)
+		for i := 0; i < objectCount; i++ {
+			key := "prefix/" + strconv.Itoa(i)
+			group.Go(func() error {
+				return benchUpload(ctx, project, "bench", key, []byte("data"))
+			})
+		}
+		require.NoError(b, group.Wait())
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			count := 0
+			objects := project.ListObjects(ctx, "bench", &uplink.ListObjectsOptions{
+				Prefix:    "prefix/",
+				Recursive: true,
+			})
+			for objects.Next() {
+				count++
+			}
+			require.NoError(b, objects.Err())
+			require.Equal(b, objectCount, count)
+		}
+	})
+}
+
+func benchUpload(ctx context.Context, project *uplink.Project, bucket, key string, data []byte) error {
+	upload, err := project.UploadObject(ctx, bucket, key, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(upload, bytes.NewReader(data))
+	if err != nil {
+		return errs.Combine(err, upload.Abort())
+	}
+
+	return upload.Commit()
+}
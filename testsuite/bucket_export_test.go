@@ -0,0 +1,127 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestBucketExportImport(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      2,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		source := openProject(t, ctx, planet)
+		defer ctx.Check(source.Close)
+
+		createBucket(t, ctx, source, "source")
+
+		contents := map[string][]byte{}
+		upload := func(key string, data []byte, options *uplink.UploadOptions, custom uplink.CustomMetadata) {
+			upload, err := source.UploadObject(ctx, "source", key, options)
+			require.NoError(t, err)
+			_, err = upload.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, upload.SetCustomMetadata(ctx, custom))
+			require.NoError(t, upload.Commit())
+			contents[key] = data
+		}
+		upload("a/object", testrand.Bytes(10*memory.KiB), nil, uplink.CustomMetadata{"key": "value"})
+		upload("b/object", testrand.Bytes(memory.KiB), nil, nil)
+		upload("c/compressed", bytes.Repeat([]byte("compressible "), 1000), &uplink.UploadOptions{Compression: uplink.CompressionGzip}, nil)
+
+		export, err := source.ExportBucketMetadata(ctx, "source", nil)
+		require.NoError(t, err)
+		require.Equal(t, "source", export.Name)
+		require.Empty(t, export.Objects)
+
+		export, err = source.ExportBucketMetadata(ctx, "source", &uplink.ExportBucketOptions{IncludeObjects: true})
+		require.NoError(t, err)
+		require.Len(t, export.Objects, 3)
+
+		// the export survives serialization
+		data, err := json.Marshal(export)
+		require.NoError(t, err)
+		var decoded uplink.BucketExport
+		require.NoError(t, json.Unmarshal(data, &decoded))
+
+		_, err = source.ExportBucketMetadata(ctx, "missing", nil)
+		require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+
+		requireObjects := func(project *uplink.Project, bucket string) {
+			objects := project.ListObjects(ctx, bucket, &uplink.ListObjectsOptions{
+				Recursive: true,
+				System:    true,
+				Custom:    true,
+			})
+			var keys []string
+			for objects.Next() {
+				object := objects.Item()
+				keys = append(keys, object.Key)
+				if object.Key == "a/object" {
+					require.Equal(t, uplink.CustomMetadata{"key": "value"}, object.Custom)
+					require.Equal(t, 10*memory.KiB.Int64(), object.System.ContentLength)
+				}
+
+				download, err := project.DownloadObject(ctx, bucket, object.Key, nil)
+				require.NoError(t, err)
+				downloaded, err := io.ReadAll(download)
+				require.NoError(t, err)
+				require.NoError(t, download.Close())
+				require.Equal(t, contents[object.Key], downloaded, object.Key)
+			}
+			require.NoError(t, objects.Err())
+			require.ElementsMatch(t, []string{"a/object", "b/object", "c/compressed"}, keys)
+		}
+
+		t.Run("same project", func(t *testing.T) {
+			bucket, err := source.ImportBucketMetadata(ctx, &decoded, &uplink.ImportBucketOptions{
+				Bucket: "copy",
+				Source: source,
+			})
+			require.NoError(t, err)
+			require.Equal(t, "copy", bucket.Name)
+			requireObjects(source, "copy")
+		})
+
+		t.Run("other project", func(t *testing.T) {
+			target, err := planet.Uplinks[1].OpenProject(ctx, planet.Satellites[0])
+			require.NoError(t, err)
+			defer ctx.Check(target.Close)
+
+			bucket, err := target.ImportBucketMetadata(ctx, &decoded, &uplink.ImportBucketOptions{
+				Source:  source,
+				Workers: 1,
+			})
+			require.NoError(t, err)
+			require.Equal(t, "source", bucket.Name)
+			requireObjects(target, "source")
+		})
+
+		t.Run("without source", func(t *testing.T) {
+			bucket, err := source.ImportBucketMetadata(ctx, &decoded, &uplink.ImportBucketOptions{Bucket: "empty"})
+			require.NoError(t, err)
+			require.Equal(t, "empty", bucket.Name)
+
+			objects := source.ListObjects(ctx, "empty", nil)
+			require.False(t, objects.Next())
+			require.NoError(t, objects.Err())
+		})
+
+		_, err = source.ImportBucketMetadata(ctx, &uplink.BucketExport{Version: 100, Name: testrand.BucketName()}, nil)
+		require.Error(t, err)
+	})
+}
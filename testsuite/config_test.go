@@ -6,19 +6,23 @@ package testsuite_test
 import (
 	"context"
 	"errors"
+	"io"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"storj.io/common/memory"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/uplink"
 	"storj.io/uplink/internal/expose"
 	"storj.io/uplink/private/transport"
+	"storj.io/uplink/testsuite/private/testnetwork"
 )
 
 func TestRequestAccess(t *testing.T) {
@@ -137,3 +141,137 @@ func TestSettingMaximumBufferSize(t *testing.T) {
 
 	assert.Equal(t, 1, d.ConnectionOptions.Manager.Stream.MaximumBufferSize)
 }
+
+func TestMetainfoTimeout(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		require.NoError(t, planet.Uplinks[0].CreateBucket(ctx, planet.Satellites[0], "bucket"))
+
+		config := uplink.Config{
+			MetainfoTimeout: time.Nanosecond,
+		}
+
+		project, err := config.OpenProject(ctx, planet.Uplinks[0].Access[planet.Satellites[0].ID()])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.StatBucket(ctx, "bucket")
+		require.Error(t, err)
+
+		// per-call timeout overrides the configured one
+		buckets := project.ListBuckets(ctx, &uplink.ListBucketsOptions{Timeout: time.Minute})
+		require.True(t, buckets.Next())
+		require.NoError(t, buckets.Err())
+		require.Equal(t, "bucket", buckets.Item().Name)
+
+		// inline objects don't need storage nodes
+		data := testrand.Bytes(memory.KiB)
+		upload, err := project.UploadObject(ctx, "bucket", "key", &uplink.UploadOptions{Timeout: time.Minute})
+		require.NoError(t, err)
+		_, err = upload.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+
+		_, err = project.StatObject(ctx, "bucket", "key")
+		require.Error(t, err)
+		object, err := project.StatObjectWithOptions(ctx, "bucket", "key", &uplink.StatObjectOptions{Timeout: time.Minute})
+		require.NoError(t, err)
+		require.EqualValues(t, len(data), object.System.ContentLength)
+
+		_, err = project.DownloadObject(ctx, "bucket", "key", nil)
+		require.Error(t, err)
+		download, err := project.DownloadObject(ctx, "bucket", "key", &uplink.DownloadOptions{Timeout: time.Minute})
+		require.NoError(t, err)
+		downloaded, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Equal(t, data, downloaded)
+	})
+}
+
+func TestLongTail(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 6,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(1, 2, 2, 6),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		config := uplink.Config{
+			LongTail: uplink.LongTailConfig{MinimumSuccesses: 5},
+		}
+
+		access, err := config.RequestAccessWithPassphrase(ctx, planet.Satellites[0].URL(), planet.Uplinks[0].Projects[0].APIKey, "mypassphrase")
+		require.NoError(t, err)
+
+		// the default upload path is used, i.e. concurrent segment uploads.
+		project, err := config.OpenProject(ctx, access)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "bucket")
+		require.NoError(t, err)
+
+		upload, err := project.UploadObject(ctx, "bucket", "object", nil)
+		require.NoError(t, err)
+		_, err = upload.Write(testrand.Bytes(10 * memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+
+		segments, err := planet.Satellites[0].Metabase.DB.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		require.GreaterOrEqual(t, len(segments[0].Pieces), 5)
+	})
+}
+
+func TestLongTail_SlowNodes(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 6,
+		UplinkCount:      1,
+		Reconfigure: testplanet.Reconfigure{
+			Satellite: testplanet.ReconfigureRS(2, 3, 4, 6),
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		shaper := testnetwork.NewShaper()
+		shaper.Attach(planet)
+
+		slow := map[storj.NodeID]bool{}
+		for _, node := range planet.StorageNodes[:2] {
+			shaper.SetStorageNodeLink(node, testnetwork.Link{Latency: time.Hour})
+			slow[node.ID()] = true
+		}
+
+		data := testrand.Bytes(10 * memory.KiB)
+
+		// the upload succeeds as soon as the success threshold is reached,
+		// without waiting for the slow nodes.
+		uploadCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		require.NoError(t, planet.Uplinks[0].Upload(uploadCtx, planet.Satellites[0], "bucket", "object", data))
+
+		segments, err := planet.Satellites[0].Metabase.DB.TestingAllSegments(ctx)
+		require.NoError(t, err)
+		require.Len(t, segments, 1)
+		require.Len(t, segments[0].Pieces, 4)
+		for _, piece := range segments[0].Pieces {
+			require.False(t, slow[piece.StorageNode])
+		}
+
+		// the download doesn't wait for a slow node either, as the other
+		// nodes have enough pieces.
+		shaper.Reset()
+		shaper.SetStorageNodeLink(planet.FindNode(segments[0].Pieces[0].StorageNode), testnetwork.Link{Latency: time.Hour})
+
+		downloadCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		downloaded, err := planet.Uplinks[0].Download(downloadCtx, planet.Satellites[0], "bucket", "object")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
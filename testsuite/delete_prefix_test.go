@@ -0,0 +1,83 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestDeletePrefix(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+		for i := 0; i < 7; i++ {
+			uploadObject(t, ctx, project, "testbucket", fmt.Sprintf("prefix/dir%d/object%d", i%2, i), memory.KiB)
+		}
+		uploadObject(t, ctx, project, "testbucket", "other/object", memory.KiB)
+
+		var progress []int64
+		result, err := project.DeletePrefix(ctx, "testbucket", "prefix/", &uplink.DeletePrefixOptions{
+			BatchSize: 3,
+			Progress: func(deleted, failed int64) {
+				require.Zero(t, failed)
+				progress = append(progress, deleted)
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(7), result.Deleted)
+		require.Empty(t, result.Failed)
+		require.Equal(t, []int64{3, 6, 7}, progress)
+
+		var keys []string
+		objects := project.ListObjects(ctx, "testbucket", &uplink.ListObjectsOptions{Recursive: true})
+		for objects.Next() {
+			keys = append(keys, objects.Item().Key)
+		}
+		require.NoError(t, objects.Err())
+		require.Equal(t, []string{"other/object"}, keys)
+
+		// nothing left to delete
+		result, err = project.DeletePrefix(ctx, "testbucket", "prefix/", nil)
+		require.NoError(t, err)
+		require.Zero(t, result.Deleted)
+
+		// objects deleted concurrently are reported as not found
+		for i := 0; i < 7; i++ {
+			uploadObject(t, ctx, project, "testbucket", fmt.Sprintf("prefix/object%d", i), memory.KiB)
+		}
+		result, err = project.DeletePrefix(ctx, "testbucket", "prefix/", &uplink.DeletePrefixOptions{
+			BatchSize: 3,
+			Progress: func(deleted, failed int64) {
+				if deleted != 3 {
+					return
+				}
+				for i := 0; i < 7; i++ {
+					_, err := project.DeleteObject(ctx, "testbucket", fmt.Sprintf("prefix/object%d", i))
+					require.NoError(t, err)
+				}
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(3), result.Deleted)
+		require.Equal(t, int64(4), result.NotFound)
+		require.Empty(t, result.Failed)
+
+		_, err = project.DeletePrefix(ctx, "missing", "prefix/", nil)
+		require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+type recordingInstrumentation struct {
+	mu    sync.Mutex
+	calls []uplink.Call
+}
+
+func (instrumentation *recordingInstrumentation) ObserveCall(call uplink.Call) {
+	instrumentation.mu.Lock()
+	defer instrumentation.mu.Unlock()
+	instrumentation.calls = append(instrumentation.calls, call)
+}
+
+func (instrumentation *recordingInstrumentation) take() []uplink.Call {
+	instrumentation.mu.Lock()
+	defer instrumentation.mu.Unlock()
+	calls := instrumentation.calls
+	instrumentation.calls = nil
+	return calls
+}
+
+func TestInstrumentation(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		instrumentation := &recordingInstrumentation{}
+
+		config := uplink.Config{Instrumentation: instrumentation}
+		project, err := config.OpenProject(ctx, planet.Uplinks[0].Access[satellite.ID()])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		_, err = project.EnsureBucket(ctx, "testbucket")
+		require.NoError(t, err)
+		require.Empty(t, instrumentation.take())
+
+		requireCall := func(name string, success bool) {
+			calls := instrumentation.take()
+			require.Len(t, calls, 1)
+			require.Equal(t, name, calls[0].Name)
+			require.Equal(t, satellite.NodeURL().String(), calls[0].Satellite)
+			require.Positive(t, calls[0].Duration)
+			require.Equal(t, success, calls[0].Err == nil)
+		}
+
+		// uploads and downloads are observed when they finish.
+		upload, err := project.UploadObject(ctx, "testbucket", "key", nil)
+		require.NoError(t, err)
+		require.Empty(t, instrumentation.take())
+		_, err = upload.Write(testrand.Bytes(memory.KiB))
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+		requireCall("UploadObject", true)
+
+		upload, err = project.UploadObject(ctx, "testbucket", "aborted", nil)
+		require.NoError(t, err)
+		require.NoError(t, upload.Abort())
+		requireCall("UploadObject", false)
+
+		_, err = project.StatObject(ctx, "testbucket", "key")
+		require.NoError(t, err)
+		requireCall("StatObject", true)
+
+		_, err = project.StatObject(ctx, "testbucket", "missing")
+		require.True(t, errors.Is(err, uplink.ErrObjectNotFound))
+		requireCall("StatObject", false)
+
+		download, err := project.DownloadObject(ctx, "testbucket", "key", nil)
+		require.NoError(t, err)
+		require.Empty(t, instrumentation.take())
+		_, err = io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		requireCall("DownloadObject", true)
+
+		_, err = project.DownloadObject(ctx, "testbucket", "missing", nil)
+		require.True(t, errors.Is(err, uplink.ErrObjectNotFound))
+		requireCall("DownloadObject", false)
+
+		objects := project.ListObjects(ctx, "testbucket", nil)
+		for objects.Next() {
+		}
+		require.NoError(t, objects.Err())
+		requireCall("ListObjects", true)
+	})
+}
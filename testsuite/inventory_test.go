@@ -0,0 +1,95 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+	"storj.io/uplink/inventory"
+	"storj.io/uplink/private/testuplink"
+)
+
+// failingWriter fails after a number of records.
+type failingWriter struct {
+	records []inventory.Record
+	failAt  int
+}
+
+func (w *failingWriter) Write(record inventory.Record) error {
+	if len(w.records) == w.failAt {
+		return errors.New("write failed")
+	}
+	w.records = append(w.records, record)
+	return nil
+}
+
+func (w *failingWriter) Flush() error { return nil }
+
+func TestInventoryExport(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		var expected []string
+		for i := 0; i < 5; i++ {
+			key := fmt.Sprintf("dir/object%d", i)
+			uploadObjectWithMetadata(t, ctx, project, "testbucket", key, memory.KiB, uplink.CustomMetadata{"index": fmt.Sprint(i)})
+			expected = append(expected, key)
+		}
+		uploadObject(t, ctx, project, "testbucket", "other", memory.KiB)
+
+		listCtx := testuplink.WithListLimit(ctx, 2)
+
+		var buf bytes.Buffer
+		_, err := inventory.Export(listCtx, project, "testbucket", inventory.NewCSVWriter(&buf, true), &inventory.Options{
+			Prefix: "dir/",
+			Custom: true,
+		})
+		require.NoError(t, err)
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Equal(t, inventory.CSVHeader, rows[0])
+		var keys []string
+		for _, row := range rows[1:] {
+			keys = append(keys, row[0])
+			require.Equal(t, "1024", row[1])
+			require.Contains(t, row[4], `"index"`)
+		}
+		require.ElementsMatch(t, expected, keys)
+
+		// a failed export can be continued from the returned cursor
+		failing := &failingWriter{failAt: 3}
+		cursor, err := inventory.Export(listCtx, project, "testbucket", failing, &inventory.Options{Prefix: "dir/"})
+		require.Error(t, err)
+		require.NotEmpty(t, cursor)
+
+		failing.failAt = -1
+		_, err = inventory.Export(listCtx, project, "testbucket", failing, &inventory.Options{Prefix: "dir/", Cursor: cursor})
+		require.NoError(t, err)
+
+		keys = nil
+		for _, record := range failing.records {
+			keys = append(keys, record.Key)
+			require.Nil(t, record.Custom)
+		}
+		require.ElementsMatch(t, expected, keys)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink/listing"
+	"storj.io/uplink/private/testuplink"
+)
+
+func TestListingParallel(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		var prefixes, expected []string
+		for i := 0; i < 4; i++ {
+			prefix := fmt.Sprintf("prefix%d/", i)
+			prefixes = append(prefixes, prefix)
+			for k := 0; k < 5; k++ {
+				key := fmt.Sprintf("%sdir/object%d", prefix, k)
+				uploadObject(t, ctx, project, "testbucket", key, memory.KiB)
+				expected = append(expected, key)
+			}
+		}
+		uploadObject(t, ctx, project, "testbucket", "unlisted", memory.KiB)
+
+		// use small pages, so every listing needs multiple requests
+		listCtx := testuplink.WithListLimit(ctx, 2)
+
+		var keys []string
+		objects := listing.Parallel(listCtx, project, "testbucket", prefixes, 3)
+		for objects.Next() {
+			require.Equal(t, memory.KiB.Int64(), objects.Item().System.ContentLength)
+			keys = append(keys, objects.Item().Key)
+		}
+		require.NoError(t, objects.Err())
+		require.ElementsMatch(t, expected, keys)
+
+		// without prefixes the whole bucket is listed
+		keys = nil
+		objects = listing.Parallel(listCtx, project, "testbucket", nil, 3)
+		for objects.Next() {
+			keys = append(keys, objects.Item().Key)
+		}
+		require.NoError(t, objects.Err())
+		require.ElementsMatch(t, append(expected, "unlisted"), keys)
+
+		// closing early stops the listings
+		objects = listing.Parallel(listCtx, project, "testbucket", prefixes, 3)
+		require.True(t, objects.Next())
+		objects.Close()
+		require.False(t, objects.Next())
+
+		objects = listing.Parallel(ctx, project, "missing-bucket", prefixes, 3)
+		require.False(t, objects.Next())
+		require.Error(t, objects.Err())
+	})
+}
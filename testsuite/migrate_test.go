@@ -0,0 +1,89 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+	"storj.io/uplink/migrate"
+)
+
+func TestMigrateBucket(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+		source, err := (uplink.Config{ContentCipher: uplink.ContentCipherSecretBox}).OpenProject(ctx, access)
+		require.NoError(t, err)
+		defer ctx.Check(source.Close)
+
+		target, err := (uplink.Config{ContentCipher: uplink.ContentCipherAESGCM}).OpenProject(ctx, access)
+		require.NoError(t, err)
+		defer ctx.Check(target.Close)
+
+		createBucket(t, ctx, source, "testbucket")
+
+		expected := map[string][]byte{}
+		for i := 0; i < 5; i++ {
+			key := fmt.Sprintf("object%d", i)
+			data := testrand.Bytes(10 * memory.KiB)
+			expected[key] = data
+
+			upload, err := source.UploadObject(ctx, "testbucket", key, nil)
+			require.NoError(t, err)
+			_, err = upload.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"index": fmt.Sprint(i)}))
+			require.NoError(t, upload.Commit())
+		}
+
+		progressFile := filepath.Join(ctx.Dir("migrate"), "progress")
+
+		result, err := migrate.Bucket(ctx, source, target, "testbucket", &migrate.Options{
+			Workers:      2,
+			Verify:       true,
+			ProgressFile: progressFile,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(5), result.Migrated)
+		require.Zero(t, result.Skipped)
+		require.Empty(t, result.Failed)
+
+		for key, data := range expected {
+			object, err := target.StatObject(ctx, "testbucket", key)
+			require.NoError(t, err)
+			require.Equal(t, key[len("object"):], object.Custom["index"])
+
+			download, err := target.DownloadObject(ctx, "testbucket", key, nil)
+			require.NoError(t, err)
+			downloaded, err := io.ReadAll(download)
+			require.NoError(t, err)
+			require.NoError(t, download.Close())
+			require.True(t, bytes.Equal(data, downloaded))
+		}
+
+		// running again resumes from the progress file
+		result, err = migrate.Bucket(ctx, source, target, "testbucket", &migrate.Options{
+			ProgressFile: progressFile,
+		})
+		require.NoError(t, err)
+		require.Zero(t, result.Migrated)
+		require.Equal(t, int64(5), result.Skipped)
+
+		_, err = migrate.Bucket(ctx, source, target, "missing", nil)
+		require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"testing"
 	"time"
 
@@ -18,6 +19,8 @@ import (
 
 	"storj.io/common/fpath"
 	"storj.io/common/memory"
+	"storj.io/common/pb"
+	"storj.io/common/storj"
 	"storj.io/common/testcontext"
 	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
@@ -552,6 +555,57 @@ func TestUploadError(t *testing.T) {
 	})
 }
 
+func TestDownloadCache(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+
+		config := uplink.Config{
+			PlaintextCacheDir: ctx.Dir("cache"),
+		}
+		project, err := config.OpenProject(ctx, planet.Uplinks[0].Access[satellite.ID()])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		download := func(options *uplink.DownloadOptions) []byte {
+			download, err := project.DownloadObject(ctx, "testbucket", "test.dat", options)
+			require.NoError(t, err)
+			defer ctx.Check(download.Close)
+			data, err := io.ReadAll(download)
+			require.NoError(t, err)
+			return data
+		}
+
+		data := testrand.Bytes(20 * memory.KiB)
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test.dat", data))
+
+		// reading the whole object populates the cache
+		require.Equal(t, data, download(nil))
+
+		entries, err := os.ReadDir(ctx.Dir("cache"))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		// the object is served from the cache without storage nodes
+		for _, node := range planet.StorageNodes {
+			require.NoError(t, planet.StopPeer(node))
+		}
+
+		require.Equal(t, data, download(nil))
+		require.Equal(t, data[100:600], download(&uplink.DownloadOptions{Offset: 100, Length: 500}))
+		require.Equal(t, data[len(data)-100:], download(&uplink.DownloadOptions{Offset: -100, Length: -1}))
+
+		// overwriting the object invalidates the cached content, the new
+		// object is small enough to be stored inline.
+		inline := testrand.Bytes(1 * memory.KiB)
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "test.dat", inline))
+		require.Equal(t, inline, download(nil))
+	})
+}
+
 func TestVeryLongDownload(t *testing.T) {
 	const (
 		segmentSize           = 100 * memory.KiB
@@ -668,3 +722,274 @@ func uploadObjectWithMetadata(t *testing.T, ctx *testcontext.Context, project *u
 
 	return upload.Info()
 }
+
+func TestDownloadObjectWithInfo(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+
+		config := uplink.Config{
+			StatReuseWindow: time.Minute,
+		}
+		project, err := config.OpenProject(ctx, planet.Uplinks[0].Access[satellite.ID()])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		read := func(download *uplink.Download, err error) []byte {
+			require.NoError(t, err)
+			defer ctx.Check(download.Close)
+			data, err := io.ReadAll(download)
+			require.NoError(t, err)
+			return data
+		}
+
+		segmentCtx := testuplink.WithMaxSegmentSize(ctx, 10*memory.KiB)
+		for _, size := range []memory.Size{0, memory.KiB, 25 * memory.KiB} {
+			data := testrand.Bytes(size)
+			require.NoError(t, planet.Uplinks[0].Upload(segmentCtx, satellite, "testbucket", "test.dat", data))
+
+			object, err := project.StatObject(ctx, "testbucket", "test.dat")
+			require.NoError(t, err)
+
+			require.Equal(t, data, read(project.DownloadObjectWithInfo(ctx, "testbucket", object, nil)))
+			if size > 0 {
+				require.Equal(t, data[1:size.Int()-1], read(project.DownloadObjectWithInfo(ctx, "testbucket", object, &uplink.DownloadOptions{
+					Offset: 1,
+					Length: size.Int64() - 2,
+				})))
+				require.Equal(t, data[size.Int()-1:], read(project.DownloadObjectWithInfo(ctx, "testbucket", object, &uplink.DownloadOptions{
+					Offset: -1,
+					Length: -1,
+				})))
+			}
+
+			// the download that follows the stat reuses its information.
+			_, err = project.StatObject(ctx, "testbucket", "test.dat")
+			require.NoError(t, err)
+			require.Equal(t, data, read(project.DownloadObject(ctx, "testbucket", "test.dat", nil)))
+		}
+
+		// uploading through the project forgets the previous stat.
+		_, err = project.StatObject(ctx, "testbucket", "test.dat")
+		require.NoError(t, err)
+		data := testrand.Bytes(15 * memory.KiB)
+		upload, err := project.UploadObject(ctx, "testbucket", "test.dat", nil)
+		require.NoError(t, err)
+		_, err = upload.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+		require.Equal(t, data, read(project.DownloadObject(ctx, "testbucket", "test.dat", nil)))
+
+		// other modifications through the project forget the previous stat too.
+		other := testrand.Bytes(5 * memory.KiB)
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, satellite, "testbucket", "other.dat", other))
+
+		_, err = project.StatObject(ctx, "testbucket", "test.dat")
+		require.NoError(t, err)
+		_, err = project.CopyObject(ctx, "testbucket", "other.dat", "testbucket", "test.dat", nil)
+		require.NoError(t, err)
+		require.Equal(t, other, read(project.DownloadObject(ctx, "testbucket", "test.dat", nil)))
+
+		_, err = project.StatObject(ctx, "testbucket", "test.dat")
+		require.NoError(t, err)
+		require.NoError(t, project.UpdateObjectMetadata(ctx, "testbucket", "test.dat", uplink.CustomMetadata{"key": "value"}, nil))
+		download, err := project.DownloadObject(ctx, "testbucket", "test.dat", nil)
+		require.NoError(t, err)
+		require.Equal(t, uplink.CustomMetadata{"key": "value"}, download.Info().Custom)
+		require.NoError(t, download.Close())
+
+		_, err = project.StatObject(ctx, "testbucket", "test.dat")
+		require.NoError(t, err)
+		_, err = project.StatObject(ctx, "testbucket", "other.dat")
+		require.NoError(t, err)
+		require.NoError(t, project.MoveObject(ctx, "testbucket", "test.dat", "testbucket", "other.dat", nil))
+		_, err = project.DownloadObject(ctx, "testbucket", "test.dat", nil)
+		require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+		download, err = project.DownloadObject(ctx, "testbucket", "other.dat", nil)
+		require.NoError(t, err)
+		require.Equal(t, uplink.CustomMetadata{"key": "value"}, download.Info().Custom)
+		require.NoError(t, download.Close())
+
+		_, err = project.StatObject(ctx, "testbucket", "other.dat")
+		require.NoError(t, err)
+		_, err = project.DeletePrefix(ctx, "testbucket", "", nil)
+		require.NoError(t, err)
+		_, err = project.DownloadObject(ctx, "testbucket", "other.dat", nil)
+		require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+
+		_, err = project.DownloadObjectWithInfo(ctx, "testbucket", nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestDownloadObject_Ranges(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		segmentCtx := testuplink.WithMaxSegmentSize(ctx, 10*memory.KiB)
+		upload := func(key string, data []byte, options *uplink.UploadOptions) {
+			upload, err := project.UploadObject(segmentCtx, "testbucket", key, options)
+			require.NoError(t, err)
+			_, err = upload.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"key": "value"}))
+			require.NoError(t, upload.Commit())
+		}
+
+		download := func(key string, data []byte, options []*uplink.DownloadOptions) {
+			for _, options := range options {
+				download, err := project.DownloadObject(ctx, "testbucket", key, options)
+				require.NoError(t, err)
+
+				downloaded, err := io.ReadAll(download)
+				require.NoError(t, err)
+				require.NoError(t, download.Close())
+
+				start, end := options.Offset, options.Offset+options.Length
+				if options.Offset < 0 {
+					start, end = int64(len(data))+options.Offset, int64(len(data))
+				} else if options.Length < 0 {
+					end = int64(len(data))
+				}
+				require.Equal(t, data[start:end], downloaded, key)
+				require.Equal(t, uplink.CustomMetadata{"key": "value"}, download.Info().Custom, key)
+			}
+		}
+
+		options := []*uplink.DownloadOptions{
+			{Offset: 0, Length: -1},
+			{Offset: 11 * 1024, Length: 12 * 1024},
+			{Offset: -100, Length: -1},
+		}
+
+		data := testrand.Bytes(25 * memory.KiB)
+		upload("test.dat", data, nil)
+		download("test.dat", data, options)
+
+		// compressed objects are decompressed.
+		compressible := bytes.Repeat([]byte("compressible "), 2000)
+		upload("compressed.dat", compressible, &uplink.UploadOptions{Compression: uplink.CompressionGzip})
+		download("compressed.dat", compressible, options[:1])
+
+		// objects uploaded before the satellite stored plain sizes are
+		// downloaded with the segment size from the stream info.
+		_, err = planet.Satellites[0].Metabase.DB.UnderlyingTagSQL().ExecContext(ctx, `
+			WITH ignore_full_scan_for_test AS (SELECT 1) UPDATE objects SET total_plain_size = 0, fixed_segment_size = 0;
+			WITH ignore_full_scan_for_test AS (SELECT 1) UPDATE segments SET plain_size = 0, plain_offset = 0;
+		`)
+		require.NoError(t, err)
+		download("test.dat", data, options)
+	})
+}
+
+func TestUploadObject_PieceHash(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		expectedAlgorithms := map[uplink.PieceHash]pb.PieceHashAlgorithm{
+			uplink.PieceHashDefault: pb.PieceHashAlgorithm_BLAKE3,
+			uplink.PieceHashSHA256:  pb.PieceHashAlgorithm_SHA256,
+			uplink.PieceHashBLAKE3:  pb.PieceHashAlgorithm_BLAKE3,
+		}
+
+		seen := map[storj.PieceID]bool{}
+		for _, hash := range []uplink.PieceHash{uplink.PieceHashDefault, uplink.PieceHashSHA256, uplink.PieceHashBLAKE3} {
+			key := fmt.Sprintf("test-%d.dat", hash)
+			data := testrand.Bytes(10 * memory.KiB)
+
+			upload, err := project.UploadObject(ctx, "testbucket", key, &uplink.UploadOptions{PieceHash: hash})
+			require.NoError(t, err)
+			_, err = upload.Write(data)
+			require.NoError(t, err)
+			require.NoError(t, upload.Commit())
+
+			download, err := project.DownloadObject(ctx, "testbucket", key, nil)
+			require.NoError(t, err)
+			downloaded, err := io.ReadAll(download)
+			require.NoError(t, err)
+			require.NoError(t, download.Close())
+			require.Equal(t, data, downloaded)
+
+			// the storage nodes store the pieces with the requested hash.
+			segments, err := planet.Satellites[0].Metabase.DB.TestingAllSegments(ctx)
+			require.NoError(t, err)
+			checked := 0
+			for _, segment := range segments {
+				if seen[segment.RootPieceID] {
+					continue
+				}
+				seen[segment.RootPieceID] = true
+
+				for _, piece := range segment.Pieces {
+					node := planet.FindNode(piece.StorageNode)
+					pieceID := segment.RootPieceID.Derive(piece.StorageNode, int32(piece.Number))
+					reader, err := node.Storage2.Store.Reader(ctx, planet.Satellites[0].ID(), pieceID)
+					require.NoError(t, err)
+					header, err := reader.GetPieceHeader()
+					require.NoError(t, err)
+					require.NoError(t, reader.Close())
+					require.Equal(t, expectedAlgorithms[hash], header.HashAlgorithm)
+					checked++
+				}
+			}
+			require.NotZero(t, checked)
+		}
+
+		_, err = project.UploadObject(ctx, "testbucket", "invalid.dat", &uplink.UploadOptions{PieceHash: 100})
+		require.Error(t, err)
+	})
+}
+
+func TestUploadObject_EncryptionBlockSize(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project, err := planet.Uplinks[0].OpenProject(ctx, planet.Satellites[0])
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		data := testrand.Bytes(100 * memory.KiB)
+
+		upload, err := project.UploadObject(ctx, "testbucket", "test.dat", &uplink.UploadOptions{EncryptionBlockSize: 1024})
+		require.NoError(t, err)
+		_, err = upload.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+
+		download, err := project.DownloadObject(ctx, "testbucket", "test.dat", nil)
+		require.NoError(t, err)
+		downloaded, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Equal(t, data, downloaded)
+
+		download, err = project.DownloadObject(ctx, "testbucket", "test.dat", &uplink.DownloadOptions{Offset: 1500, Length: 3000})
+		require.NoError(t, err)
+		downloaded, err = io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Equal(t, data[1500:4500], downloaded)
+
+		for _, blockSize := range []int32{-1, 16, int32(planet.Satellites[0].Config.Metainfo.MaxSegmentSize) + 1} {
+			_, err = project.UploadObject(ctx, "testbucket", "invalid.dat", &uplink.UploadOptions{EncryptionBlockSize: blockSize})
+			require.Error(t, err)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -129,6 +130,93 @@ func TestListObjects_IncludeMetadata(t *testing.T) {
 	})
 }
 
+func TestListObjects_CustomMetadataFilter(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		for i := 0; i < 10; i++ {
+			metadata := uplink.CustomMetadata{"index": strconv.Itoa(i)}
+			if i%3 == 0 {
+				metadata["color"] = "red"
+			}
+			uploadObjectWithMetadata(t, ctx, project, "testbucket", fmt.Sprintf("%d.dat", i), 1*memory.KiB, metadata)
+		}
+
+		// use a small page size so filtering has to cross pages
+		list := listObjects(testuplink.WithListLimit(ctx, 2), t, project, "testbucket", &uplink.ListObjectsOptions{
+			CustomMetadataFilter: map[string]string{"color": "red"},
+		})
+
+		var keys []string
+		for list.Next() {
+			keys = append(keys, list.Item().Key)
+			require.Nil(t, list.Item().Custom)
+		}
+		require.NoError(t, list.Err())
+		require.ElementsMatch(t, []string{"0.dat", "3.dat", "6.dat", "9.dat"}, keys)
+
+		list = listObjects(ctx, t, project, "testbucket", &uplink.ListObjectsOptions{
+			Custom:               true,
+			CustomMetadataFilter: map[string]string{"color": "red", "index": "3"},
+		})
+		require.True(t, list.Next())
+		require.Equal(t, "3.dat", list.Item().Key)
+		require.Equal(t, uplink.CustomMetadata{"color": "red", "index": "3"}, list.Item().Custom)
+		assertNoNextObject(t, list)
+	})
+}
+
+func TestListObjects_SortBy(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		// the keys are uploaded in a different order than their sizes.
+		sizes := map[string]memory.Size{"c": 3 * memory.KiB, "a": 2 * memory.KiB, "b": 1 * memory.KiB}
+		for _, key := range []string{"c", "a", "b"} {
+			uploadObject(t, ctx, project, "testbucket", key, sizes[key])
+		}
+		uploadObject(t, ctx, project, "testbucket", "dir/d", memory.KiB)
+
+		keys := func(options *uplink.ListObjectsOptions) []string {
+			// use a small page size so sorting has to cross pages
+			list := listObjects(testuplink.WithListLimit(ctx, 2), t, project, "testbucket", options)
+
+			var keys []string
+			for list.Next() {
+				keys = append(keys, list.Item().Key)
+			}
+			require.NoError(t, list.Err())
+			return keys
+		}
+
+		require.Equal(t, []string{"a", "b", "c", "dir/"}, keys(&uplink.ListObjectsOptions{SortBy: uplink.SortByKey}))
+		require.Equal(t, []string{"dir/", "c", "b", "a"}, keys(&uplink.ListObjectsOptions{SortBy: uplink.SortByKey, SortDescending: true}))
+		require.Equal(t, []string{"dir/", "c", "a", "b"}, keys(&uplink.ListObjectsOptions{SortBy: uplink.SortByCreated}))
+		require.Equal(t, []string{"dir/", "b", "a", "c"}, keys(&uplink.ListObjectsOptions{SortBy: uplink.SortBySize}))
+		require.Equal(t, []string{"c", "a", "b", "dir/"}, keys(&uplink.ListObjectsOptions{SortBy: uplink.SortBySize, SortDescending: true}))
+
+		// system metadata is only returned when requested
+		list := listObjects(ctx, t, project, "testbucket", &uplink.ListObjectsOptions{SortBy: uplink.SortBySize})
+		require.True(t, list.Next())
+		require.True(t, list.Next())
+		require.Zero(t, list.Item().System.ContentLength)
+	})
+}
+
 func TestListObjects_TwoObjects(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount:   1,
@@ -69,6 +69,54 @@ func TestListBucketsWithAttribution(t *testing.T) {
 	})
 }
 
+func TestGetBucketAttribution(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+		// map bucket -> user agent
+		testCases := map[string]string{
+			"aaa":  "foo",
+			"aaab": "",
+			"bbb":  "boo",
+		}
+
+		for bucket, userAgent := range testCases {
+			func() {
+				config := uplink.Config{
+					UserAgent: userAgent,
+				}
+
+				project, err := config.OpenProject(ctx, access)
+				require.NoError(t, err)
+				defer ctx.Check(project.Close)
+
+				_, err = project.CreateBucket(ctx, bucket)
+				require.NoError(t, err)
+			}()
+		}
+
+		project, err := uplink.OpenProject(ctx, access)
+		require.NoError(t, err)
+		defer ctx.Check(project.Close)
+
+		for bucketName, userAgent := range testCases {
+			attribution, err := bucket.GetBucketAttribution(ctx, project, bucketName)
+			require.NoError(t, err)
+			require.Equal(t, userAgent, attribution)
+		}
+
+		_, err = bucket.GetBucketAttribution(ctx, project, "aab")
+		require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+
+		_, err = bucket.GetBucketAttribution(ctx, project, "")
+		require.ErrorIs(t, err, uplink.ErrBucketNameInvalid)
+	})
+}
+
 func TestGetBucketLocation(t *testing.T) {
 	testplanet.Run(t, testplanet.Config{
 		SatelliteCount: 1, UplinkCount: 1,
@@ -0,0 +1,268 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package testfaults implements programmable storage node faults for
+// testplanet, so failover and long-tail behavior of uplink can be tested
+// deterministically.
+package testfaults
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storagenode"
+	"storj.io/storj/storagenode/blobstore"
+	"storj.io/storj/storagenode/blobstore/filestore"
+	"storj.io/storj/storagenode/pieces"
+)
+
+var _ blobstore.Blobs = (*FaultyBlobs)(nil)
+
+// ErrDropped is returned for piece uploads that were dropped by a fault.
+var ErrDropped = errs.Class("testfaults: dropped upload")
+
+// Faults describes the faults injected into piece operations of a storage
+// node.
+type Faults struct {
+	// DropUploads is the percentage, from 0 to 100, of piece uploads that fail.
+	DropUploads int
+	// Delay is the latency added to every piece operation.
+	Delay time.Duration
+	// CorruptDownloads makes reads of piece data return corrupted bytes.
+	CorruptDownloads bool
+}
+
+// Injector creates fault injecting databases for testplanet storage nodes
+// and keeps track of them by storage node index.
+//
+// Use StorageNodeDB as testplanet.Reconfigure.StorageNodeDB.
+type Injector struct {
+	seed int64
+
+	mu  sync.Mutex
+	dbs map[int]*FaultyDB
+}
+
+// NewInjector creates a new Injector. Random decisions of the storage node
+// with index i are derived from seed+i, so runs with the same seed drop the
+// same uploads.
+func NewInjector(seed int64) *Injector {
+	return &Injector{
+		seed: seed,
+		dbs:  map[int]*FaultyDB{},
+	}
+}
+
+// StorageNodeDB wraps db of the storage node with the specified index.
+func (injector *Injector) StorageNodeDB(index int, db storagenode.DB, log *zap.Logger) (storagenode.DB, error) {
+	faulty := NewFaultyDB(log, db, injector.seed+int64(index))
+
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+	injector.dbs[index] = faulty
+
+	return faulty, nil
+}
+
+// Node returns the database of the storage node with the specified index.
+// It returns nil when the node has not been created.
+func (injector *Injector) Node(index int) *FaultyDB {
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+	return injector.dbs[index]
+}
+
+// SetFaults configures the faults of all storage nodes.
+func (injector *Injector) SetFaults(faults Faults) {
+	injector.mu.Lock()
+	defer injector.mu.Unlock()
+	for _, db := range injector.dbs {
+		db.SetFaults(faults)
+	}
+}
+
+// FaultyDB implements a storage node DB with programmable faults.
+type FaultyDB struct {
+	storagenode.DB
+	blobs *FaultyBlobs
+}
+
+// NewFaultyDB creates a new fault injecting storage node DB wrapping db.
+// Use SetFaults to configure the faults of piece operations.
+func NewFaultyDB(log *zap.Logger, db storagenode.DB, seed int64) *FaultyDB {
+	return &FaultyDB{
+		DB: db,
+		blobs: &FaultyBlobs{
+			Blobs: db.Pieces(),
+			log:   log,
+			rand:  rand.New(rand.NewSource(seed)),
+		},
+	}
+}
+
+// Pieces returns the blob store.
+func (faulty *FaultyDB) Pieces() blobstore.Blobs {
+	return faulty.blobs
+}
+
+// SetFaults configures the faults injected into piece operations.
+func (faulty *FaultyDB) SetFaults(faults Faults) {
+	faulty.blobs.SetFaults(faults)
+}
+
+// FaultyBlobs implements a blob store with programmable faults.
+type FaultyBlobs struct {
+	blobstore.Blobs
+	log *zap.Logger
+
+	mu     sync.Mutex
+	faults Faults
+	rand   *rand.Rand
+}
+
+// SetFaults configures the faults injected into piece operations.
+func (faulty *FaultyBlobs) SetFaults(faults Faults) {
+	faulty.mu.Lock()
+	defer faulty.mu.Unlock()
+	faulty.faults = faults
+}
+
+// Faults returns the currently configured faults.
+func (faulty *FaultyBlobs) Faults() Faults {
+	faulty.mu.Lock()
+	defer faulty.mu.Unlock()
+	return faulty.faults
+}
+
+// shouldDrop decides whether the next upload is dropped.
+func (faulty *FaultyBlobs) shouldDrop() bool {
+	faulty.mu.Lock()
+	defer faulty.mu.Unlock()
+	if faulty.faults.DropUploads <= 0 {
+		return false
+	}
+	return faulty.rand.Intn(100) < faulty.faults.DropUploads
+}
+
+// sleep waits for the configured delay or until ctx is canceled.
+func (faulty *FaultyBlobs) sleep(ctx context.Context) error {
+	delay := faulty.Faults().Delay
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Create creates a new blob that can be written optionally takes a size
+// argument for performance improvements, -1 is unknown size.
+func (faulty *FaultyBlobs) Create(ctx context.Context, ref blobstore.BlobRef, size int64) (blobstore.BlobWriter, error) {
+	if err := faulty.sleep(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	if faulty.shouldDrop() {
+		faulty.log.Debug("dropping piece upload", zap.Binary("key", ref.Key))
+		return nil, ErrDropped.New("%x", ref.Key)
+	}
+	return faulty.Blobs.Create(ctx, ref, size)
+}
+
+// Open opens a reader with the specified namespace and key.
+func (faulty *FaultyBlobs) Open(ctx context.Context, ref blobstore.BlobRef) (blobstore.BlobReader, error) {
+	if err := faulty.sleep(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	reader, err := faulty.Blobs.Open(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return faulty.wrapReader(reader), nil
+}
+
+// OpenWithStorageFormat opens a reader for the already-located blob, avoiding the potential need
+// to check multiple storage formats to find the blob.
+func (faulty *FaultyBlobs) OpenWithStorageFormat(ctx context.Context, ref blobstore.BlobRef, formatVer blobstore.FormatVersion) (blobstore.BlobReader, error) {
+	if err := faulty.sleep(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	reader, err := faulty.Blobs.OpenWithStorageFormat(ctx, ref, formatVer)
+	if err != nil {
+		return nil, err
+	}
+	return faulty.wrapReader(reader), nil
+}
+
+// Stat looks up disk metadata on the blob file.
+func (faulty *FaultyBlobs) Stat(ctx context.Context, ref blobstore.BlobRef) (blobstore.BlobInfo, error) {
+	if err := faulty.sleep(ctx); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return faulty.Blobs.Stat(ctx, ref)
+}
+
+// wrapReader returns a corrupting reader when downloads should be corrupted.
+func (faulty *FaultyBlobs) wrapReader(reader blobstore.BlobReader) blobstore.BlobReader {
+	if !faulty.Faults().CorruptDownloads {
+		return reader
+	}
+
+	var dataOffset int64
+	if reader.StorageFormatVersion() >= filestore.FormatV1 {
+		dataOffset = pieces.V1PieceHeaderReservedArea
+	}
+	return &corruptReader{BlobReader: reader, dataOffset: dataOffset}
+}
+
+// corruptReader flips the bits of all piece data bytes it returns. The piece
+// header is left intact, so the storage node serves the piece as usual.
+type corruptReader struct {
+	blobstore.BlobReader
+	dataOffset int64
+	position   int64
+}
+
+// Read implements io.Reader.
+func (reader *corruptReader) Read(p []byte) (int, error) {
+	n, err := reader.BlobReader.Read(p)
+	reader.corrupt(p[:n], reader.position)
+	reader.position += int64(n)
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt.
+func (reader *corruptReader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := reader.BlobReader.ReadAt(p, off)
+	reader.corrupt(p[:n], off)
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (reader *corruptReader) Seek(offset int64, whence int) (int64, error) {
+	position, err := reader.BlobReader.Seek(offset, whence)
+	if err == nil {
+		reader.position = position
+	}
+	return position, err
+}
+
+// corrupt flips the bits of data bytes in p, which was read at offset.
+func (reader *corruptReader) corrupt(p []byte, offset int64) {
+	for i := range p {
+		if offset+int64(i) >= reader.dataOffset {
+			p[i] ^= 0xFF
+		}
+	}
+}
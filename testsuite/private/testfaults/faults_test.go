@@ -0,0 +1,72 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testfaults_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink/testsuite/private/testfaults"
+)
+
+func TestDropUploads(t *testing.T) {
+	injector := testfaults.NewInjector(0)
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNodeDB: injector.StorageNodeDB,
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+
+		data := testrand.Bytes(10 * memory.KiB)
+
+		// all uploads dropped, the upload cannot reach the success threshold
+		injector.SetFaults(testfaults.Faults{DropUploads: 100})
+		require.Error(t, uplink.Upload(ctx, satellite, "testbucket", "dropped", data))
+
+		// a single faulty node must not fail the upload
+		injector.SetFaults(testfaults.Faults{})
+		injector.Node(0).SetFaults(testfaults.Faults{DropUploads: 100})
+		require.NoError(t, uplink.Upload(ctx, satellite, "testbucket", "one-faulty", data))
+
+		downloaded, err := uplink.Download(ctx, satellite, "testbucket", "one-faulty")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
+
+func TestDelay(t *testing.T) {
+	injector := testfaults.NewInjector(0)
+
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+		Reconfigure: testplanet.Reconfigure{
+			StorageNodeDB: injector.StorageNodeDB,
+		},
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		satellite := planet.Satellites[0]
+		uplink := planet.Uplinks[0]
+
+		data := testrand.Bytes(10 * memory.KiB)
+
+		// slow nodes are cut off by the long tail cancellation
+		injector.Node(0).SetFaults(testfaults.Faults{Delay: time.Hour})
+		injector.Node(1).SetFaults(testfaults.Faults{Delay: time.Hour})
+		require.NoError(t, uplink.Upload(ctx, satellite, "testbucket", "slow", data))
+
+		injector.SetFaults(testfaults.Faults{})
+		downloaded, err := uplink.Download(ctx, satellite, "testbucket", "slow")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
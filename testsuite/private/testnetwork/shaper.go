@@ -0,0 +1,189 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package testnetwork simulates network conditions between uplink and the
+// nodes of testplanet, so timeouts and long-tail behavior of uplink can be
+// tested under WAN-like latency and bandwidth.
+package testnetwork
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"storj.io/common/memory"
+	"storj.io/storj/private/testplanet"
+)
+
+// Link describes the conditions of the connections to an address.
+type Link struct {
+	// Latency is added to every write.
+	Latency time.Duration
+	// Bandwidth limits the bytes per second read and written by a single
+	// connection. Zero means unlimited.
+	Bandwidth memory.Size
+}
+
+// Shaper dials connections with simulated network conditions.
+//
+// Use Attach to shape the connections of the testplanet uplinks, or
+// DialContext as uplink.Config.DialContext.
+type Shaper struct {
+	dialer net.Dialer
+
+	mu          sync.Mutex
+	defaultLink Link
+	links       map[string]Link
+}
+
+// NewShaper creates a new Shaper without any simulated conditions.
+func NewShaper() *Shaper {
+	return &Shaper{
+		links: map[string]Link{},
+	}
+}
+
+// SetDefault sets the conditions for addresses without a link of their own.
+func (shaper *Shaper) SetDefault(link Link) {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+	shaper.defaultLink = link
+}
+
+// SetLink sets the conditions of connections to address. The change applies
+// to existing connections as well.
+func (shaper *Shaper) SetLink(address string, link Link) {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+	shaper.links[address] = link
+}
+
+// SetStorageNodeLink sets the conditions of connections to node.
+func (shaper *Shaper) SetStorageNodeLink(node *testplanet.StorageNode, link Link) {
+	shaper.SetLink(node.Addr(), link)
+}
+
+// Attach makes the uplinks of planet dial through the shaper. It applies to
+// the projects opened with the uplink helpers, like GetProject, Upload and
+// Download, and to projects opened with the Config of the uplink.
+func (shaper *Shaper) Attach(planet *testplanet.Planet) {
+	for _, uplink := range planet.Uplinks {
+		uplink.Config.DialContext = shaper.DialContext
+	}
+}
+
+// Reset removes all simulated conditions.
+func (shaper *Shaper) Reset() {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+	shaper.defaultLink = Link{}
+	shaper.links = map[string]Link{}
+}
+
+// link returns the conditions of connections to address.
+func (shaper *Shaper) link(address string) Link {
+	shaper.mu.Lock()
+	defer shaper.mu.Unlock()
+	if link, ok := shaper.links[address]; ok {
+		return link
+	}
+	return shaper.defaultLink
+}
+
+// DialContext dials address and applies the conditions of its link to the
+// connection.
+func (shaper *Shaper) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if err := sleep(ctx, shaper.link(address).Latency); err != nil {
+		return nil, err
+	}
+
+	conn, err := shaper.dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shapedConn{
+		Conn:    conn,
+		shaper:  shaper,
+		address: address,
+		closed:  make(chan struct{}),
+	}, nil
+}
+
+// shapedConn delays reads and writes according to the link of address.
+type shapedConn struct {
+	net.Conn
+	shaper  *Shaper
+	address string
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Read implements net.Conn.
+func (conn *shapedConn) Read(p []byte) (int, error) {
+	n, err := conn.Conn.Read(p)
+	if !conn.wait(transferTime(n, conn.shaper.link(conn.address).Bandwidth)) && err == nil {
+		err = net.ErrClosed
+	}
+	return n, err
+}
+
+// Write implements net.Conn.
+func (conn *shapedConn) Write(p []byte) (int, error) {
+	link := conn.shaper.link(conn.address)
+	if !conn.wait(link.Latency + transferTime(len(p), link.Bandwidth)) {
+		return 0, net.ErrClosed
+	}
+	return conn.Conn.Write(p)
+}
+
+// Close implements net.Conn. It unblocks reads and writes that are delayed.
+func (conn *shapedConn) Close() error {
+	conn.closeOnce.Do(func() { close(conn.closed) })
+	return conn.Conn.Close()
+}
+
+// wait waits for delay and returns false when the connection was closed in
+// the meantime.
+func (conn *shapedConn) wait(delay time.Duration) bool {
+	if delay <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-conn.closed:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// transferTime returns how long transferring n bytes takes with bandwidth.
+func transferTime(n int, bandwidth memory.Size) time.Duration {
+	if n <= 0 || bandwidth <= 0 {
+		return 0
+	}
+	return time.Duration(int64(n) * int64(time.Second) / bandwidth.Int64())
+}
+
+// sleep waits for delay or until ctx is canceled.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
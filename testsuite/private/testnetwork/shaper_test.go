@@ -0,0 +1,105 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testnetwork_test
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink/testsuite/private/testnetwork"
+)
+
+func TestShaper(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+
+	ctx.Go(func() error {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(io.Discard, conn)
+		return errs.Combine(err, conn.Close())
+	})
+
+	shaper := testnetwork.NewShaper()
+	shaper.SetLink(listener.Addr().String(), testnetwork.Link{
+		Latency:   100 * time.Millisecond,
+		Bandwidth: 10 * memory.KiB,
+	})
+
+	start := time.Now()
+	conn, err := shaper.DialContext(ctx, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	_, err = conn.Write(make([]byte, 5*memory.KiB.Int()))
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	// dial latency, write latency and half a second for the transfer.
+	require.GreaterOrEqual(t, time.Since(start), 700*time.Millisecond)
+}
+
+func TestShaper_Close(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ctx.Check(listener.Close)
+
+	ctx.Go(func() error {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(io.Discard, conn)
+		return errs.Combine(err, conn.Close())
+	})
+
+	shaper := testnetwork.NewShaper()
+	conn, err := shaper.DialContext(ctx, "tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	shaper.SetLink(listener.Addr().String(), testnetwork.Link{Latency: time.Hour})
+
+	// closing unblocks a delayed write
+	time.AfterFunc(100*time.Millisecond, func() { _ = conn.Close() })
+	_, err = conn.Write([]byte("data"))
+	require.ErrorIs(t, err, net.ErrClosed)
+}
+
+func TestShaper_SlowNodes(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 10, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		shaper := testnetwork.NewShaper()
+		shaper.Attach(planet)
+		for _, node := range planet.StorageNodes[:2] {
+			shaper.SetStorageNodeLink(node, testnetwork.Link{Latency: time.Hour})
+		}
+
+		data := testrand.Bytes(10 * memory.KiB)
+
+		// unreachable nodes are cut off by the long tail cancellation
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, planet.Satellites[0], "testbucket", "slow", data))
+
+		shaper.Reset()
+
+		downloaded, err := planet.Uplinks[0].Download(ctx, planet.Satellites[0], "testbucket", "slow")
+		require.NoError(t, err)
+		require.Equal(t, data, downloaded)
+	})
+}
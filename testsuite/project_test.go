@@ -4,11 +4,19 @@
 package testsuite_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
 
+	"storj.io/common/memory"
 	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
 	"storj.io/storj/private/testplanet"
 	"storj.io/uplink"
 )
@@ -46,3 +54,126 @@ func TestProject_OpenProjectMalformedUserAgent(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "user agent")
 }
+
+func TestProject_Capabilities(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		capabilities, err := project.Capabilities(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uplink.Supported, capabilities.Versioning)
+		require.Equal(t, uplink.Supported, capabilities.ServerSideCopy)
+		require.Equal(t, uplink.Supported, capabilities.ServerSideMove)
+		require.Equal(t, uplink.Supported, capabilities.Multipart)
+		require.Positive(t, capabilities.LocalSegmentSize)
+		require.Equal(t, uplink.MaxCustomMetadataSize, capabilities.DefaultMaxCustomMetadataSize)
+
+		require.NoError(t, capabilities.VerifyUploadOptions(nil))
+		require.NoError(t, capabilities.VerifyUploadOptions(&uplink.UploadOptions{
+			Compression:         uplink.CompressionGzip,
+			EncryptionBlockSize: 1024,
+		}))
+		require.Error(t, capabilities.VerifyUploadOptions(&uplink.UploadOptions{
+			EncryptionBlockSize: int32(capabilities.LocalSegmentSize + 1),
+		}))
+		require.Error(t, capabilities.VerifyUploadOptions(&uplink.UploadOptions{
+			Compression: "unknown",
+		}))
+	})
+}
+
+func TestProject_Shutdown(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		require.NoError(t, planet.Uplinks[0].Upload(ctx, planet.Satellites[0], "bucket", "existing", testrand.Bytes(100*memory.KiB)))
+
+		project := openProject(t, ctx, planet)
+
+		upload, err := project.UploadObject(ctx, "bucket", "uploading", nil)
+		require.NoError(t, err)
+		_, err = upload.Write(testrand.Bytes(100 * memory.KiB))
+		require.NoError(t, err)
+
+		download, err := project.DownloadObject(ctx, "bucket", "existing", nil)
+		require.NoError(t, err)
+		_, err = download.Read(make([]byte, 10))
+		require.NoError(t, err)
+
+		shutdownCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		require.NoError(t, project.Shutdown(shutdownCtx))
+
+		require.True(t, errors.Is(upload.Commit(), uplink.ErrUploadDone))
+		_, err = io.ReadAll(download)
+		require.Error(t, err)
+		closeErr := download.Close()
+		require.Equal(t, closeErr, download.Close())
+
+		_, err = project.UploadObject(ctx, "bucket", "after-shutdown", nil)
+		require.ErrorIs(t, err, uplink.ErrProjectShutdown)
+		_, err = project.DownloadObject(ctx, "bucket", "existing", nil)
+		require.ErrorIs(t, err, uplink.ErrProjectShutdown)
+
+		project = openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		_, err = project.StatObject(ctx, "bucket", "uploading")
+		require.True(t, errors.Is(err, uplink.ErrObjectNotFound))
+	})
+}
+
+func TestProject_SharedScheduler(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		_, err := uplink.NewScheduler(uplink.SchedulerOptions{})
+		require.Error(t, err)
+
+		scheduler, err := uplink.NewScheduler(uplink.SchedulerOptions{
+			MaxConcurrentPieces:   1,
+			MaxConcurrentSegments: 1,
+		})
+		require.NoError(t, err)
+
+		projectInfo := planet.Uplinks[0].Projects[0]
+		access, err := uplink.RequestAccessWithPassphrase(ctx, projectInfo.Satellite.URL(), projectInfo.APIKey, "mypassphrase")
+		require.NoError(t, err)
+
+		config := uplink.Config{Scheduler: scheduler}
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+		createBucket(t, ctx, project, "bucket")
+
+		var group errgroup.Group
+		for i := 0; i < 2; i++ {
+			key := fmt.Sprintf("object-%d", i)
+			group.Go(func() error {
+				project, err := config.OpenProject(ctx, access)
+				if err != nil {
+					return err
+				}
+				defer ctx.Check(project.Close)
+
+				upload, err := project.UploadObject(ctx, "bucket", key, nil)
+				if err != nil {
+					return err
+				}
+				if _, err := upload.Write(testrand.Bytes(100 * memory.KiB)); err != nil {
+					return err
+				}
+				return upload.Commit()
+			})
+		}
+		require.NoError(t, group.Wait())
+	})
+}
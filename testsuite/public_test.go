@@ -0,0 +1,71 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestOpenPublicPrefix(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "site")
+		index := uploadObject(t, ctx, project, "site", "public/index.html", memory.KiB)
+		uploadObject(t, ctx, project, "site", "public/style.css", memory.KiB)
+		uploadObject(t, ctx, project, "site", "private/secret.txt", memory.KiB)
+
+		access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+		public, err := uplink.OpenPublicPrefix(ctx, access, "site", "public/")
+		require.NoError(t, err)
+		defer ctx.Check(public.Close)
+
+		var keys []string
+		objects := public.ListObjects(ctx, nil)
+		for objects.Next() {
+			keys = append(keys, objects.Item().Key)
+		}
+		require.NoError(t, objects.Err())
+		require.ElementsMatch(t, []string{"public/index.html", "public/style.css"}, keys)
+
+		object, err := public.StatObject(ctx, "public/index.html")
+		require.NoError(t, err)
+		require.Equal(t, index.System.ContentLength, object.System.ContentLength)
+
+		download, err := public.DownloadObject(ctx, "public/index.html", nil)
+		require.NoError(t, err)
+		data, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Len(t, data, memory.KiB.Int())
+
+		_, err = public.StatObject(ctx, "private/secret.txt")
+		require.True(t, errors.Is(err, uplink.ErrPermissionDenied))
+
+		objects = public.ListObjects(ctx, &uplink.ListObjectsOptions{Prefix: "private/"})
+		require.False(t, objects.Next())
+		require.True(t, errors.Is(objects.Err(), uplink.ErrPermissionDenied))
+
+		// a prefix without the separator would share keys like "public-old/"
+		_, err = uplink.OpenPublicPrefix(ctx, access, "site", "public")
+		require.Error(t, err)
+
+		_, err = public.StatObject(ctx, "public-old/index.html")
+		require.True(t, errors.Is(err, uplink.ErrPermissionDenied))
+	})
+}
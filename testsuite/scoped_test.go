@@ -0,0 +1,83 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestOpenScopedProject(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 4,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		access := planet.Uplinks[0].Access[planet.Satellites[0].ID()]
+
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+		uploadObject(t, ctx, project, "testbucket", "tenant/a", memory.KiB)
+		uploadObject(t, ctx, project, "testbucket", "other/b", memory.KiB)
+
+		// unrestricted and multi-prefix access grants have no scope
+		_, err := uplink.OpenScopedProject(ctx, access)
+		require.Error(t, err)
+
+		multiple, err := access.Share(uplink.FullPermission(),
+			uplink.SharePrefix{Bucket: "testbucket", Prefix: "tenant/"},
+			uplink.SharePrefix{Bucket: "testbucket", Prefix: "other/"},
+		)
+		require.NoError(t, err)
+		_, err = uplink.OpenScopedProject(ctx, multiple)
+		require.Error(t, err)
+
+		shared, err := access.Share(uplink.FullPermission(), uplink.SharePrefix{Bucket: "testbucket", Prefix: "tenant/"})
+		require.NoError(t, err)
+
+		// the scope survives serialization
+		serialized, err := shared.Serialize()
+		require.NoError(t, err)
+		shared, err = uplink.ParseAccess(serialized)
+		require.NoError(t, err)
+
+		scoped, err := uplink.OpenScopedProject(ctx, shared)
+		require.NoError(t, err)
+		defer ctx.Check(scoped.Close)
+
+		require.Equal(t, "testbucket", scoped.Bucket())
+		require.Equal(t, "tenant/", scoped.Prefix())
+
+		upload, err := scoped.UploadObject(ctx, "tenant/c", nil)
+		require.NoError(t, err)
+		_, err = upload.Write([]byte("data"))
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+
+		var keys []string
+		objects := scoped.ListObjects(ctx, nil)
+		for objects.Next() {
+			keys = append(keys, objects.Item().Key)
+		}
+		require.NoError(t, objects.Err())
+		require.ElementsMatch(t, []string{"tenant/a", "tenant/c"}, keys)
+
+		_, err = scoped.StatObject(ctx, "tenant/a")
+		require.NoError(t, err)
+
+		_, err = scoped.StatObject(ctx, "other/b")
+		require.ErrorIs(t, err, uplink.ErrPermissionDenied)
+
+		_, err = scoped.DeleteObject(ctx, "tenant/c")
+		require.NoError(t, err)
+	})
+}
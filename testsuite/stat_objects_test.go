@@ -0,0 +1,65 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/memory"
+	"storj.io/common/testcontext"
+	"storj.io/storj/private/testplanet"
+	"storj.io/uplink"
+)
+
+func TestStatObjects(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount:   1,
+		StorageNodeCount: 0,
+		UplinkCount:      1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		var keys []string
+		for i := 0; i < 5; i++ {
+			key := fmt.Sprintf("object%d", i)
+			uploadObject(t, ctx, project, "testbucket", key, memory.KiB)
+			keys = append(keys, key)
+		}
+
+		results, err := project.StatObjects(ctx, "testbucket", keys)
+		require.NoError(t, err)
+		require.Len(t, results, len(keys))
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			require.Equal(t, keys[i], result.Key)
+			require.Equal(t, keys[i], result.Object.Key)
+			require.Equal(t, memory.KiB.Int64(), result.Object.System.ContentLength)
+		}
+
+		// missing keys don't fail the other keys
+		results, err = project.StatObjects(ctx, "testbucket", []string{"object0", "missing", "object1", ""})
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, "object0", results[0].Object.Key)
+		require.ErrorIs(t, results[1].Err, uplink.ErrObjectNotFound)
+		require.Nil(t, results[1].Object)
+		require.NoError(t, results[2].Err)
+		require.Equal(t, "object1", results[2].Object.Key)
+		require.ErrorIs(t, results[3].Err, uplink.ErrObjectKeyInvalid)
+
+		results, err = project.StatObjects(ctx, "testbucket", nil)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		_, err = project.StatObjects(ctx, "", keys)
+		require.ErrorIs(t, err, uplink.ErrBucketNameInvalid)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"os"
 	"strconv"
 	"testing"
 	"time"
@@ -572,6 +573,176 @@ func TestUploadLimits(t *testing.T) {
 	})
 }
 
+func TestUploadCompression(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		upload := func(key string, data []byte) *uplink.Object {
+			upload, err := project.UploadObject(ctx, "testbucket", key, &uplink.UploadOptions{
+				Compression: uplink.CompressionGzip,
+			})
+			require.NoError(t, err)
+			_, err = io.Copy(upload, bytes.NewReader(data))
+			require.NoError(t, err)
+			require.NoError(t, upload.Commit())
+			return upload.Info()
+		}
+
+		download := func(key string, options *uplink.DownloadOptions) []byte {
+			download, err := project.DownloadObject(ctx, "testbucket", key, options)
+			require.NoError(t, err)
+			defer ctx.Check(download.Close)
+			data, err := io.ReadAll(download)
+			require.NoError(t, err)
+			return data
+		}
+
+		compressible := bytes.Repeat([]byte("compressible "), 100000)
+		object := upload("compressible", compressible)
+		require.Equal(t, string(uplink.CompressionGzip), object.Custom[uplink.CompressionMetadataKey])
+		require.Less(t, object.System.ContentLength, int64(len(compressible)))
+
+		require.Equal(t, compressible, download("compressible", nil))
+
+		// ranges refer to the uncompressed data, so they are rejected.
+		for _, options := range []*uplink.DownloadOptions{
+			{Offset: 1000, Length: -1},
+			{Offset: 1000, Length: 500},
+			{Offset: -100, Length: -1},
+		} {
+			_, err := project.DownloadObject(ctx, "testbucket", "compressible", options)
+			require.Error(t, err)
+		}
+
+		// data, which only claims to be compressed, is downloaded as is.
+		claimed := bytes.Repeat([]byte("not compressed "), 1000)
+		upload, err := project.UploadObject(ctx, "testbucket", "claimed", nil)
+		require.NoError(t, err)
+		require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{
+			uplink.CompressionMetadataKey: string(uplink.CompressionGzip),
+		}))
+		_, err = upload.Write(claimed)
+		require.NoError(t, err)
+		require.NoError(t, upload.Commit())
+		require.Equal(t, claimed, download("claimed", nil))
+
+		// random data is not compressible, so it is stored as is
+		incompressible := testrand.Bytes(200 * memory.KiB)
+		object = upload("incompressible", incompressible)
+		require.NotContains(t, object.Custom, uplink.CompressionMetadataKey)
+		require.Equal(t, int64(len(incompressible)), object.System.ContentLength)
+		require.Equal(t, incompressible, download("incompressible", nil))
+
+		_, err = project.UploadObject(ctx, "testbucket", "unknown", &uplink.UploadOptions{
+			Compression: "unknown",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestUploadInfo(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		expires := time.Now().Add(time.Hour).Truncate(time.Second)
+		for _, size := range []memory.Size{memory.KiB, 100 * memory.KiB} {
+			key := size.String()
+			upload, err := project.UploadObject(ctx, "testbucket", key, &uplink.UploadOptions{
+				Expires: expires,
+			})
+			require.NoError(t, err)
+			require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"key": "value"}))
+
+			_, err = upload.Write(testrand.Bytes(size))
+			require.NoError(t, err)
+			require.NoError(t, upload.Commit())
+
+			info := upload.Info()
+			object, err := project.StatObject(ctx, "testbucket", key)
+			require.NoError(t, err)
+
+			require.Equal(t, object.Key, info.Key)
+			require.Equal(t, object.System.ContentLength, info.System.ContentLength)
+			require.True(t, object.System.Created.Equal(info.System.Created))
+			require.True(t, object.System.Expires.Equal(info.System.Expires))
+			require.Equal(t, object.Custom, info.Custom)
+		}
+	})
+}
+
+func TestUploadReadFrom(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		for _, tc := range []struct {
+			name          string
+			size          memory.Size
+			contentLength int64
+		}{
+			{"inline unknown length", 2 * memory.KiB, 0},
+			{"inline exact length", 2 * memory.KiB, 2048},
+			{"remote unknown length", 700 * memory.KiB, 0},
+			{"remote exact length", 700 * memory.KiB, 700 * 1024},
+			{"wrong length", 700 * memory.KiB, 10},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				data := testrand.Bytes(tc.size)
+
+				upload, err := project.UploadObject(ctx, "testbucket", tc.name, &uplink.UploadOptions{
+					ContentLength: tc.contentLength,
+				})
+				require.NoError(t, err)
+
+				var _ io.ReaderFrom = upload
+				reader := &bufferSizeReader{Reader: bytes.NewReader(data)}
+				n, err := upload.ReadFrom(reader)
+				require.NoError(t, err)
+				require.Equal(t, int64(len(data)), n)
+				// too small content lengths don't shrink the buffer below
+				// 32 KiB.
+				require.GreaterOrEqual(t, reader.max, 32*1024)
+				require.NoError(t, upload.Commit())
+				require.Equal(t, int64(len(data)), upload.Info().System.ContentLength)
+
+				download, err := project.DownloadObject(ctx, "testbucket", tc.name, nil)
+				require.NoError(t, err)
+				defer ctx.Check(download.Close)
+				downloaded, err := io.ReadAll(download)
+				require.NoError(t, err)
+				require.Equal(t, data, downloaded)
+			})
+		}
+	})
+}
+
+// bufferSizeReader records the largest buffer passed to Read.
+type bufferSizeReader struct {
+	io.Reader
+	max int
+}
+
+func (r *bufferSizeReader) Read(p []byte) (int, error) {
+	if len(p) > r.max {
+		r.max = len(p)
+	}
+	return r.Reader.Read(p)
+}
+
 func requireWriteEventuallyReturns(tb testing.TB, w io.Writer, data []byte, expectErr error) {
 	require.Eventually(tb, func() bool {
 		_, err := w.Write(data)
@@ -580,3 +751,80 @@ func requireWriteEventuallyReturns(tb testing.TB, w io.Writer, data []byte, expe
 		return errors.Is(err, expectErr)
 	}, time.Second*5, time.Millisecond*10)
 }
+
+func TestUploadSpool(t *testing.T) {
+	testplanet.Run(t, testplanet.Config{
+		SatelliteCount: 1, StorageNodeCount: 4, UplinkCount: 1,
+	}, func(t *testing.T, ctx *testcontext.Context, planet *testplanet.Planet) {
+		project := openProject(t, ctx, planet)
+		defer ctx.Check(project.Close)
+
+		createBucket(t, ctx, project, "testbucket")
+
+		spoolDir := ctx.Dir("spool")
+		expectedData := testrand.Bytes(10 * memory.KiB)
+
+		requireSpoolEmpty := func() {
+			entries, err := os.ReadDir(spoolDir)
+			require.NoError(t, err)
+			require.Empty(t, entries)
+		}
+
+		// the spool file is removed on abort, unless it's kept
+		upload, err := project.UploadObject(ctx, "testbucket", "key", &uplink.UploadOptions{
+			SpoolDir: spoolDir,
+		})
+		require.NoError(t, err)
+		_, err = io.Copy(upload, bytes.NewReader(expectedData))
+		require.NoError(t, err)
+		require.NoError(t, upload.Abort())
+		requireSpoolEmpty()
+		_, err = upload.Spool()
+		require.Error(t, err)
+
+		upload, err = project.UploadObject(ctx, "testbucket", "key", &uplink.UploadOptions{
+			SpoolDir:  spoolDir,
+			KeepSpool: true,
+		})
+		require.NoError(t, err)
+		_, err = io.Copy(upload, bytes.NewReader(expectedData))
+		require.NoError(t, err)
+
+		// spool is only available after a failure
+		_, err = upload.Spool()
+		require.Error(t, err)
+
+		require.NoError(t, upload.Abort())
+
+		spool, err := upload.Spool()
+		require.NoError(t, err)
+
+		_, err = upload.Spool()
+		require.Error(t, err)
+
+		// retry the upload from the spool
+		upload, err = project.UploadObject(ctx, "testbucket", "key", &uplink.UploadOptions{
+			SpoolDir: spoolDir,
+		})
+		require.NoError(t, err)
+		_, err = io.Copy(upload, spool)
+		require.NoError(t, err)
+		require.NoError(t, spool.Close())
+		require.NoError(t, upload.Commit())
+		requireSpoolEmpty()
+
+		download, err := project.DownloadObject(ctx, "testbucket", "key", nil)
+		require.NoError(t, err)
+		defer ctx.Check(download.Close)
+		data, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.Equal(t, expectedData, data)
+
+		// spool is not available for uploads without SpoolDir
+		upload, err = project.UploadObject(ctx, "testbucket", "other", nil)
+		require.NoError(t, err)
+		require.NoError(t, upload.Abort())
+		_, err = upload.Spool()
+		require.Error(t, err)
+	})
+}
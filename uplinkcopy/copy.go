@@ -0,0 +1,267 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package uplinkcopy copies objects between projects, which may be on
+// different satellites, by streaming the data through the uplink.
+//
+// Server-side copies with Project.CopyObject only work within a project.
+// Copy downloads the object from the source project and uploads it to the
+// destination project. Large objects are copied as multipart uploads with
+// parts copied in parallel, and failed parts are retried without starting
+// over.
+package uplinkcopy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+	"golang.org/x/sync/errgroup"
+
+	"storj.io/common/memory"
+	"storj.io/uplink"
+	"storj.io/uplink/internal/expose"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for this package.
+var Error = errs.Class("uplinkcopy")
+
+const (
+	// DefaultPartSize is the part size used when Options.PartSize is zero.
+	DefaultPartSize = 64 * memory.MiB
+	// DefaultConcurrency is the number of parts copied in parallel when
+	// Options.Concurrency is zero.
+	DefaultConcurrency = 4
+	// DefaultRetries is the number of retries used when Options.Retries is
+	// zero.
+	DefaultRetries = 3
+)
+
+// retryDelay is the delay before the first retry. It doubles with every
+// retry.
+var retryDelay = 100 * time.Millisecond
+
+// Options contains additional options for Copy.
+type Options struct {
+	// PartSize is the size of the parts. Objects larger than PartSize are
+	// copied as multipart uploads, so it must be at least the minimum part
+	// size of the destination satellite, which is 5 MiB by default.
+	PartSize int64
+	// Concurrency is the number of parts copied in parallel.
+	Concurrency int
+	// Retries is the number of times a failed part is copied again.
+	// Negative values disable retries.
+	Retries int
+
+	// Progress is called after every copied part with the number of bytes
+	// copied so far and the size of the object. It isn't called
+	// concurrently.
+	Progress func(copied, total int64)
+}
+
+// Copy copies the object srcKey in srcBucket of src to dstKey in dstBucket
+// of dst. Custom metadata, expiration and compression of the object are
+// preserved.
+//
+// Objects up to Options.PartSize and compressed objects are copied with a
+// single upload. Larger objects are copied with a multipart upload, which
+// is aborted when the copy fails.
+func Copy(ctx context.Context, src uplink.ProjectAPI, srcBucket, srcKey string, dst uplink.ProjectAPI, dstBucket, dstKey string, options *Options) (_ *uplink.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = DefaultPartSize.Int64()
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.Retries == 0 {
+		opts.Retries = DefaultRetries
+	}
+	if opts.PartSize < 0 {
+		return nil, Error.New("invalid part size %d", opts.PartSize)
+	}
+	if opts.Concurrency < 0 {
+		return nil, Error.New("invalid concurrency %d", opts.Concurrency)
+	}
+
+	object, err := src.StatObject(ctx, srcBucket, srcKey)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &copier{
+		src: src, srcBucket: srcBucket, srcKey: srcKey,
+		dst: dst, dstBucket: dstBucket, dstKey: dstKey,
+		object: object,
+		opts:   opts,
+	}
+
+	custom := object.Custom.Clone()
+	compression := uplink.Compression(custom[uplink.CompressionMetadataKey])
+	delete(custom, uplink.CompressionMetadataKey)
+
+	// ranges of compressed objects need the whole object to be downloaded,
+	// so they can't be copied in parts.
+	if compression != uplink.CompressionNone || object.System.ContentLength <= opts.PartSize {
+		return c.copyObject(ctx)
+	}
+	return c.copyMultipart(ctx, custom)
+}
+
+// copier copies a single object.
+type copier struct {
+	src               uplink.ProjectAPI
+	srcBucket, srcKey string
+	dst               uplink.ProjectAPI
+	dstBucket, dstKey string
+	object            *uplink.Object
+	opts              Options
+
+	mu     sync.Mutex
+	copied int64
+}
+
+// copyObject copies the object with a single upload.
+func (c *copier) copyObject(ctx context.Context) (object *uplink.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = c.retry(ctx, func() (err error) {
+		object, err = expose.ReuploadObject(ctx, c.src, c.srcBucket, c.object, c.dst, c.dstBucket, c.dstKey, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.progress(c.object.System.ContentLength)
+	return object, nil
+}
+
+// copyMultipart copies the object with a multipart upload.
+func (c *copier) copyMultipart(ctx context.Context, custom uplink.CustomMetadata) (_ *uplink.Object, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := c.dst.BeginUpload(ctx, c.dstBucket, c.dstKey, &uplink.UploadOptions{
+		Expires: c.object.System.Expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, c.dst.AbortUpload(context.Background(), c.dstBucket, c.dstKey, info.UploadID))
+		}
+	}()
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(c.opts.Concurrency)
+
+	size := c.object.System.ContentLength
+	for partNumber, offset := uint32(1), int64(0); offset < size && groupCtx.Err() == nil; partNumber, offset = partNumber+1, offset+c.opts.PartSize {
+		partNumber, offset := partNumber, offset
+		length := c.opts.PartSize
+		if offset+length > size {
+			length = size - offset
+		}
+
+		group.Go(func() error {
+			err := c.retry(groupCtx, func() error {
+				return c.copyPart(groupCtx, info.UploadID, partNumber, offset, length)
+			})
+			if err != nil {
+				return err
+			}
+			c.progress(length)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.dst.CommitUpload(ctx, c.dstBucket, c.dstKey, info.UploadID, &uplink.CommitUploadOptions{
+		CustomMetadata: custom,
+	})
+}
+
+// copyPart copies length bytes at offset of the object as the part with
+// partNumber.
+func (c *copier) copyPart(ctx context.Context, uploadID string, partNumber uint32, offset, length int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	download, err := c.src.DownloadObject(ctx, c.srcBucket, c.srcKey, &uplink.DownloadOptions{
+		Offset: offset,
+		Length: length,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { err = errs.Combine(err, download.Close()) }()
+
+	upload, err := c.dst.UploadPart(ctx, c.dstBucket, c.dstKey, uploadID, partNumber)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(upload, download)
+	if err == nil && n != length {
+		err = Error.New("part %d has %d bytes, expected %d; the source object changed", partNumber, n, length)
+	}
+	if err != nil {
+		return errs.Combine(err, upload.Abort())
+	}
+	return upload.Commit()
+}
+
+// retry calls fn until it succeeds, returns a permanent error or the
+// retries are used up.
+func (c *copier) retry(ctx context.Context, fn func() error) error {
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= c.opts.Retries || permanent(err) || ctx.Err() != nil {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		delay *= 2
+	}
+}
+
+// progress adds n to the copied bytes and reports the progress.
+func (c *copier) progress(n int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.copied += n
+	if c.opts.Progress != nil {
+		c.opts.Progress(c.copied, c.object.System.ContentLength)
+	}
+}
+
+// permanent returns whether retrying can't fix err.
+func permanent(err error) bool {
+	return errors.Is(err, uplink.ErrObjectNotFound) ||
+		errors.Is(err, uplink.ErrBucketNotFound) ||
+		errors.Is(err, uplink.ErrUploadIDInvalid) ||
+		errors.Is(err, uplink.ErrPermissionDenied)
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplinkcopy_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+	"storj.io/uplink/uplinkcopy"
+	"storj.io/uplink/uplinktest"
+)
+
+func TestCopy(t *testing.T) {
+	ctx := context.Background()
+
+	src, dst := uplinktest.NewProject(), uplinktest.NewProject()
+	_, err := src.CreateBucket(ctx, "src")
+	require.NoError(t, err)
+	_, err = dst.CreateBucket(ctx, "dst")
+	require.NoError(t, err)
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, size := range []int{0, 1, 10, 11, 64} {
+		data := testrand.BytesInt(size)
+
+		upload, err := src.UploadObject(ctx, "src", "key", &uplink.UploadOptions{Expires: expires})
+		require.NoError(t, err)
+		_, err = upload.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, upload.SetCustomMetadata(ctx, uplink.CustomMetadata{"k": "v"}))
+		require.NoError(t, upload.Commit())
+
+		var copied, total int64
+		object, err := uplinkcopy.Copy(ctx, src, "src", "key", dst, "dst", "copy", &uplinkcopy.Options{
+			PartSize:    10,
+			Concurrency: 2,
+			Progress: func(c, t int64) {
+				copied, total = c, t
+			},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, size, object.System.ContentLength)
+		require.EqualValues(t, size, copied)
+		require.EqualValues(t, size, total)
+
+		stat, err := dst.StatObject(ctx, "dst", "copy")
+		require.NoError(t, err)
+		require.Equal(t, uplink.CustomMetadata{"k": "v"}, stat.Custom)
+		require.Equal(t, expires, stat.System.Expires)
+
+		download, err := dst.DownloadObject(ctx, "dst", "copy", nil)
+		require.NoError(t, err)
+		downloaded, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Equal(t, data, downloaded)
+	}
+
+	uploads := dst.ListUploads(ctx, "dst", nil)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+}
+
+func TestCopy_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	src, dst := uplinktest.NewProject(), uplinktest.NewProject()
+	_, err := src.CreateBucket(ctx, "src")
+	require.NoError(t, err)
+	_, err = dst.CreateBucket(ctx, "dst")
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(25)
+	upload, err := src.UploadObject(ctx, "src", "key", nil)
+	require.NoError(t, err)
+	_, err = upload.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, upload.Commit())
+
+	// failed parts are retried.
+	injected := errors.New("injected")
+	dst.FailNext("UploadPart", injected)
+	_, err = uplinkcopy.Copy(ctx, src, "src", "key", dst, "dst", "copy", &uplinkcopy.Options{PartSize: 10})
+	require.NoError(t, err)
+
+	// without retries the copy fails and the upload is aborted.
+	src.FailNext("DownloadObject", injected)
+	_, err = uplinkcopy.Copy(ctx, src, "src", "key", dst, "dst", "failed", &uplinkcopy.Options{PartSize: 10, Retries: -1})
+	require.ErrorIs(t, err, injected)
+
+	_, err = uplinkcopy.Copy(ctx, src, "src", "missing", dst, "dst", "missing", nil)
+	require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+
+	_, err = uplinkcopy.Copy(ctx, src, "src", "key", dst, "missing", "key", &uplinkcopy.Options{PartSize: 10})
+	require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+
+	_, err = uplinkcopy.Copy(ctx, src, "src", "key", dst, "dst", "key", &uplinkcopy.Options{PartSize: -1})
+	require.Error(t, err)
+
+	uploads := dst.ListUploads(ctx, "dst", nil)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+
+	_, err = dst.StatObject(ctx, "dst", "failed")
+	require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+}
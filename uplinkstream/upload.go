@@ -0,0 +1,222 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package uplinkstream uploads streams of unknown length, such as pipes and
+// network responses, using multipart uploads with parallel parts.
+package uplinkstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/context2"
+	"storj.io/common/memory"
+	"storj.io/uplink"
+)
+
+// Error is the error class for this package.
+var Error = errs.Class("uplinkstream")
+
+const (
+	// DefaultPartSize is the part size used when Options.PartSize is zero.
+	DefaultPartSize = 64 * memory.MiB
+	// DefaultConcurrency is the number of parts uploaded in parallel when
+	// Options.Concurrency is zero.
+	DefaultConcurrency = 4
+
+	// abortTimeout limits how long aborting a failed multipart upload may
+	// take, as the context of the upload may already be canceled.
+	abortTimeout = time.Minute
+)
+
+// Options contains additional options for Upload.
+type Options struct {
+	// PartSize is the size of the parts. All parts but the last one have
+	// this size, so it must be at least the minimum part size of the
+	// satellite, which is 5 MiB by default.
+	PartSize int64
+	// Concurrency is the number of parts uploaded in parallel. Upload
+	// buffers at most Concurrency+1 parts in memory, the parts being
+	// uploaded and the part being read.
+	Concurrency int
+
+	// Expires is the expiration time of the object. When Expires is zero,
+	// there is no expiration.
+	Expires time.Time
+	// CustomMetadata is the custom metadata of the object.
+	CustomMetadata uplink.CustomMetadata
+}
+
+// Upload uploads the data read from r until io.EOF to bucket and key.
+//
+// Data that fits into a single part is uploaded with UploadObject. Longer
+// data is uploaded with a multipart upload, where up to
+// Options.Concurrency parts are uploaded in parallel while the next part is
+// read. When the upload fails, the multipart upload is aborted.
+func Upload(ctx context.Context, project uplink.ProjectAPI, bucket, key string, r io.Reader, options *Options) (_ *uplink.Object, err error) {
+	opts := Options{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.PartSize == 0 {
+		opts.PartSize = DefaultPartSize.Int64()
+	}
+	if opts.Concurrency == 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.PartSize < 0 {
+		return nil, Error.New("invalid part size %d", opts.PartSize)
+	}
+	if opts.Concurrency < 0 {
+		return nil, Error.New("invalid concurrency %d", opts.Concurrency)
+	}
+
+	first := make([]byte, opts.PartSize)
+	n, err := io.ReadFull(r, first)
+	switch {
+	case errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF):
+		return uploadObject(ctx, project, bucket, key, first[:n], opts)
+	case err != nil:
+		return nil, Error.Wrap(err)
+	}
+
+	return uploadMultipart(ctx, project, bucket, key, first, r, opts)
+}
+
+// uploadObject uploads data, which fits into a single part.
+func uploadObject(ctx context.Context, project uplink.ProjectAPI, bucket, key string, data []byte, opts Options) (_ *uplink.Object, err error) {
+	upload, err := project.UploadObject(ctx, bucket, key, &uplink.UploadOptions{
+		Expires:       opts.Expires,
+		ContentLength: int64(len(data)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, upload.Abort())
+		}
+	}()
+
+	if _, err := upload.Write(data); err != nil {
+		return nil, err
+	}
+	if opts.CustomMetadata != nil {
+		if err := upload.SetCustomMetadata(ctx, opts.CustomMetadata); err != nil {
+			return nil, err
+		}
+	}
+	if err := upload.Commit(); err != nil {
+		return nil, err
+	}
+	return upload.Info(), nil
+}
+
+// uploadMultipart uploads first and the rest of r as a multipart upload.
+func uploadMultipart(ctx context.Context, project uplink.ProjectAPI, bucket, key string, first []byte, r io.Reader, opts Options) (_ *uplink.Object, err error) {
+	info, err := project.BeginUpload(ctx, bucket, key, &uplink.UploadOptions{
+		Expires: opts.Expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			abortCtx, cancel := context.WithTimeout(context2.WithoutCancellation(ctx), abortTimeout)
+			defer cancel()
+			err = errs.Combine(err, project.AbortUpload(abortCtx, bucket, key, info.UploadID))
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	// buffers bounds the number of parts in memory. Together with first,
+	// there is a buffer for every part being uploaded and one for the part
+	// being read. They are allocated when needed, so short uploads don't
+	// allocate all of them.
+	buffers := make(chan []byte, opts.Concurrency+1)
+	allocated := 1
+
+	buf, n := first, len(first)
+	for partNumber := uint32(1); n > 0; partNumber++ {
+		wg.Add(1)
+		go func(partNumber uint32, buf []byte, n int) {
+			defer wg.Done()
+			defer func() { buffers <- buf }()
+
+			if err := uploadPart(ctx, project, bucket, key, info.UploadID, partNumber, buf[:n]); err != nil {
+				fail(err)
+			}
+		}(partNumber, buf, n)
+
+		if allocated <= opts.Concurrency {
+			buf = make([]byte, opts.PartSize)
+			allocated++
+		} else {
+			select {
+			case buf = <-buffers:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		var rerr error
+		n, rerr = io.ReadFull(r, buf)
+		if rerr != nil && !errors.Is(rerr, io.EOF) && !errors.Is(rerr, io.ErrUnexpectedEOF) {
+			fail(Error.Wrap(rerr))
+			break
+		}
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return project.CommitUpload(ctx, bucket, key, info.UploadID, &uplink.CommitUploadOptions{
+		CustomMetadata: opts.CustomMetadata,
+	})
+}
+
+// uploadPart uploads data as the part with partNumber.
+func uploadPart(ctx context.Context, project uplink.ProjectAPI, bucket, key, uploadID string, partNumber uint32, data []byte) (err error) {
+	upload, err := project.UploadPart(ctx, bucket, key, uploadID, partNumber)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errs.Combine(err, upload.Abort())
+		}
+	}()
+
+	if _, err := upload.Write(data); err != nil {
+		return err
+	}
+	return upload.Commit()
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplinkstream_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+	"storj.io/uplink/uplinkstream"
+	"storj.io/uplink/uplinktest"
+)
+
+func TestUpload(t *testing.T) {
+	ctx := context.Background()
+
+	project := uplinktest.NewProject()
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	for _, size := range []int{0, 1, 9, 10, 11, 64} {
+		data := testrand.BytesInt(size)
+		// io.MultiReader hides the length of the data, like a pipe.
+		object, err := uplinkstream.Upload(ctx, project, "bucket", "key", io.MultiReader(bytes.NewReader(data)), &uplinkstream.Options{
+			PartSize:       10,
+			Concurrency:    2,
+			CustomMetadata: uplink.CustomMetadata{"k": "v"},
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, size, object.System.ContentLength)
+		require.Equal(t, uplink.CustomMetadata{"k": "v"}, object.Custom)
+
+		download, err := project.DownloadObject(ctx, "bucket", "key", nil)
+		require.NoError(t, err)
+		downloaded, err := io.ReadAll(download)
+		require.NoError(t, err)
+		require.NoError(t, download.Close())
+		require.Equal(t, data, downloaded)
+	}
+
+	uploads := project.ListUploads(ctx, "bucket", nil)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+}
+
+func TestUpload_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	project := uplinktest.NewProject()
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	options := &uplinkstream.Options{PartSize: 10, Concurrency: 3}
+
+	injected := errors.New("injected")
+	project.FailNext("UploadPart", injected)
+	_, err = uplinkstream.Upload(ctx, project, "bucket", "key", bytes.NewReader(testrand.BytesInt(100)), options)
+	require.ErrorIs(t, err, injected)
+
+	readErr := errors.New("read failed")
+	_, err = uplinkstream.Upload(ctx, project, "bucket", "key", io.MultiReader(bytes.NewReader(testrand.BytesInt(25)), &failingReader{readErr}), options)
+	require.ErrorIs(t, err, readErr)
+
+	_, err = uplinkstream.Upload(ctx, project, "missing", "key", bytes.NewReader(testrand.BytesInt(25)), options)
+	require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+
+	_, err = uplinkstream.Upload(ctx, project, "bucket", "key", bytes.NewReader(nil), &uplinkstream.Options{PartSize: -1})
+	require.Error(t, err)
+
+	// failed uploads are aborted.
+	uploads := project.ListUploads(ctx, "bucket", nil)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+
+	_, err = project.StatObject(ctx, "bucket", "key")
+	require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+}
+
+func TestUpload_Concurrency(t *testing.T) {
+	ctx := context.Background()
+
+	project := &blockingProject{
+		ProjectAPI: uplinktest.NewProject(),
+		started:    make(chan struct{}, 10),
+		release:    make(chan struct{}),
+	}
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(100)
+	reader := &notifyingReader{r: bytes.NewReader(data), at: 30, reached: make(chan struct{})}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := uplinkstream.Upload(ctx, project, "bucket", "key", reader, &uplinkstream.Options{
+			PartSize:    10,
+			Concurrency: 2,
+		})
+		done <- err
+	}()
+
+	// two parts are uploaded while the third one is read.
+	<-project.started
+	<-project.started
+	<-reader.reached
+	close(project.release)
+	require.NoError(t, <-done)
+
+	download, err := project.DownloadObject(ctx, "bucket", "key", nil)
+	require.NoError(t, err)
+	downloaded, err := io.ReadAll(download)
+	require.NoError(t, err)
+	require.NoError(t, download.Close())
+	require.Equal(t, data, downloaded)
+}
+
+func TestUpload_AbortCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	project := &abortingProject{ProjectAPI: uplinktest.NewProject()}
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	// the upload is canceled after the multipart upload was started.
+	reader := io.MultiReader(bytes.NewReader(testrand.BytesInt(15)), &cancelingReader{cancel: cancel})
+	_, err = uplinkstream.Upload(ctx, project, "bucket", "key", reader, &uplinkstream.Options{PartSize: 10})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// the upload is aborted with a context that isn't canceled, but bounded.
+	require.NoError(t, project.abortErr)
+	require.True(t, project.abortHasDeadline)
+
+	uploads := project.ListUploads(context.Background(), "bucket", nil)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+}
+
+// abortingProject records the context of AbortUpload.
+type abortingProject struct {
+	uplink.ProjectAPI
+	abortErr         error
+	abortHasDeadline bool
+}
+
+func (project *abortingProject) AbortUpload(ctx context.Context, bucket, key, uploadID string) error {
+	project.abortErr = ctx.Err()
+	_, project.abortHasDeadline = ctx.Deadline()
+	return project.ProjectAPI.AbortUpload(ctx, bucket, key, uploadID)
+}
+
+// cancelingReader cancels the context when it's read.
+type cancelingReader struct{ cancel func() }
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	r.cancel()
+	return 0, context.Canceled
+}
+
+// blockingProject blocks uploading parts until release is closed.
+type blockingProject struct {
+	uplink.ProjectAPI
+	started chan struct{}
+	release chan struct{}
+}
+
+func (project *blockingProject) UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber uint32) (*uplink.PartUpload, error) {
+	project.started <- struct{}{}
+	<-project.release
+	return project.ProjectAPI.UploadPart(ctx, bucket, key, uploadID, partNumber)
+}
+
+// notifyingReader closes reached once at bytes were read.
+type notifyingReader struct {
+	r       io.Reader
+	read    int
+	at      int
+	reached chan struct{}
+}
+
+func (r *notifyingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if r.read < r.at && r.read+n >= r.at {
+		close(r.reached)
+	}
+	r.read += n
+	return n, err
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read(p []byte) (int, error) { return 0, r.err }
@@ -0,0 +1,263 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplinktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"math"
+	"sort"
+	"time"
+
+	"storj.io/common/base58"
+	"storj.io/uplink"
+	"storj.io/uplink/internal/expose"
+)
+
+// pendingUpload is a multipart upload started with BeginUpload.
+type pendingUpload struct {
+	bucket string
+	info   uplink.UploadInfo
+	parts  map[uint32]*part
+}
+
+type part struct {
+	info uplink.Part
+	data []byte
+}
+
+// BeginUpload starts a multipart upload to bucket and key.
+func (project *Project) BeginUpload(ctx context.Context, bucket, key string, options *uplink.UploadOptions) (uplink.UploadInfo, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("BeginUpload"); err != nil {
+		return uplink.UploadInfo{}, err
+	}
+	if _, err := project.bucket(bucket); err != nil {
+		return uplink.UploadInfo{}, err
+	}
+	if key == "" {
+		return uplink.UploadInfo{}, errwrapf("%w (%q)", uplink.ErrObjectKeyInvalid, key)
+	}
+
+	// upload IDs are derived from a counter, so they are deterministic.
+	project.uploadID++
+	id := make([]byte, 16)
+	binary.BigEndian.PutUint64(id[8:], uint64(project.uploadID))
+
+	upload := &pendingUpload{
+		bucket: bucket,
+		info: uplink.UploadInfo{
+			UploadID: base58.CheckEncode(id, 1),
+			Key:      key,
+			System: uplink.SystemMetadata{
+				Created: project.now(),
+			},
+			Custom: uplink.CustomMetadata{},
+		},
+		parts: make(map[uint32]*part),
+	}
+	if options != nil {
+		upload.info.System.Expires = options.Expires
+	}
+	project.uploads[upload.info.UploadID] = upload
+
+	return upload.info, nil
+}
+
+// UploadPart starts an upload of a part of a multipart upload. The part is
+// stored when the upload is committed.
+func (project *Project) UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber uint32) (*uplink.PartUpload, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("UploadPart"); err != nil {
+		return nil, err
+	}
+	if _, err := project.upload(bucket, key, uploadID); err != nil {
+		return nil, err
+	}
+	if partNumber >= math.MaxInt32 {
+		return nil, uplinkError.New("partNumber should be less than max(int32)")
+	}
+
+	return expose.PartUploadNewLocal(ctx, bucket, key, &uplink.Part{PartNumber: partNumber}, func(data []byte, info *uplink.Part) (time.Time, error) {
+		project.mu.Lock()
+		defer project.mu.Unlock()
+
+		if project.closed {
+			return time.Time{}, Error.New("project is closed")
+		}
+		upload, err := project.upload(bucket, key, uploadID)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		stored := *info
+		stored.Size = int64(len(data))
+		stored.Modified = project.now()
+		stored.ETag = append([]byte(nil), info.ETag...)
+		upload.parts[partNumber] = &part{
+			info: stored,
+			data: append([]byte(nil), data...),
+		}
+		return stored.Modified, nil
+	}), nil
+}
+
+// CommitUpload commits a multipart upload. The parts are joined in the
+// order of their part numbers.
+func (project *Project) CommitUpload(ctx context.Context, bucket, key, uploadID string, options *uplink.CommitUploadOptions) (*uplink.Object, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("CommitUpload"); err != nil {
+		return nil, err
+	}
+	upload, err := project.upload(bucket, key, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := uplink.Object{
+		Key: key,
+		System: uplink.SystemMetadata{
+			Expires: upload.info.System.Expires,
+		},
+	}
+	if options != nil {
+		if err := options.CustomMetadata.Verify(); err != nil {
+			return nil, uplinkError.Wrap(err)
+		}
+		info.Custom = options.CustomMetadata
+	}
+
+	var data bytes.Buffer
+	for _, part := range upload.sortedParts() {
+		data.Write(part.data)
+	}
+	if err := project.putObject(bucket, info, data.Bytes()); err != nil {
+		return nil, err
+	}
+	delete(project.uploads, uploadID)
+
+	return project.buckets[bucket].objects[key].clone(), nil
+}
+
+// AbortUpload aborts a multipart upload.
+func (project *Project) AbortUpload(ctx context.Context, bucket, key, uploadID string) error {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("AbortUpload"); err != nil {
+		return err
+	}
+	if _, err := project.upload(bucket, key, uploadID); err != nil {
+		return err
+	}
+	delete(project.uploads, uploadID)
+	return nil
+}
+
+// ListUploads returns an iterator over the uncommitted uploads in bucket,
+// sorted by key.
+func (project *Project) ListUploads(ctx context.Context, bucket string, options *uplink.ListUploadsOptions) *uplink.UploadIterator {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("ListUploads"); err != nil {
+		return expose.UploadIteratorNewLocal(nil, options, err)
+	}
+	if _, err := project.bucket(bucket); err != nil {
+		return expose.UploadIteratorNewLocal(nil, options, err)
+	}
+
+	opts := uplink.ListUploadsOptions{}
+	if options != nil {
+		opts = *options
+	}
+
+	byKey := make(map[string][]*pendingUpload)
+	for _, upload := range project.uploads {
+		if upload.bucket == bucket {
+			byKey[upload.info.Key] = append(byKey[upload.info.Key], upload)
+		}
+	}
+
+	var uploads []*uplink.UploadInfo
+	for _, entry := range list(sortedKeys(byKey), opts.Prefix, opts.Cursor, opts.Recursive) {
+		if entry.isPrefix {
+			uploads = append(uploads, &uplink.UploadInfo{Key: entry.key, IsPrefix: true})
+			continue
+		}
+		pending := byKey[entry.key]
+		sort.Slice(pending, func(i, k int) bool { return pending[i].info.UploadID < pending[k].info.UploadID })
+		for _, upload := range pending {
+			info := upload.info
+			info.Custom = upload.info.Custom.Clone()
+			uploads = append(uploads, &info)
+		}
+	}
+	return expose.UploadIteratorNewLocal(uploads, options, nil)
+}
+
+// ListUploadParts returns an iterator over the committed parts of a
+// multipart upload, sorted by part number.
+func (project *Project) ListUploadParts(ctx context.Context, bucket, key, uploadID string, options *uplink.ListUploadPartsOptions) *uplink.PartIterator {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("ListUploadParts"); err != nil {
+		return expose.PartIteratorNewLocal(nil, err)
+	}
+	upload, err := project.upload(bucket, key, uploadID)
+	if err != nil {
+		return expose.PartIteratorNewLocal(nil, err)
+	}
+
+	var cursor uint32
+	if options != nil {
+		cursor = options.Cursor
+	}
+
+	var parts []*uplink.Part
+	for _, part := range upload.sortedParts() {
+		if part.info.PartNumber > cursor {
+			info := part.info
+			parts = append(parts, &info)
+		}
+	}
+	return expose.PartIteratorNewLocal(parts, nil)
+}
+
+// upload returns the pending upload with uploadID for bucket and key.
+func (project *Project) upload(bucket, key, uploadID string) (*pendingUpload, error) {
+	if _, err := project.bucket(bucket); err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectKeyInvalid, key)
+	}
+	if _, version, err := base58.CheckDecode(uploadID); err != nil || version != 1 {
+		return nil, uplinkError.Wrap(uplink.ErrUploadIDInvalid)
+	}
+
+	upload, ok := project.uploads[uploadID]
+	if !ok || upload.bucket != bucket || upload.info.Key != key {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectNotFound, key)
+	}
+	return upload, nil
+}
+
+// sortedParts returns the committed parts sorted by part number.
+func (upload *pendingUpload) sortedParts() []*part {
+	parts := make([]*part, 0, len(upload.parts))
+	for _, part := range upload.parts {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, k int) bool { return parts[i].info.PartNumber < parts[k].info.PartNumber })
+	return parts
+}
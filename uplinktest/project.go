@@ -0,0 +1,790 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package uplinktest implements uplink.ProjectAPI in memory, so applications
+// can be unit tested without a satellite or storage nodes.
+package uplinktest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+	"storj.io/uplink"
+	"storj.io/uplink/internal/expose"
+)
+
+// Error is the error class for errors that only the in-memory project
+// returns.
+var Error = errs.Class("uplinktest")
+
+// uplinkError is the error class of the errors returned by uplink, so the
+// in-memory project returns the same error messages.
+var uplinkError = errs.Class("uplink")
+
+// Project is an in-memory implementation of uplink.ProjectAPI.
+//
+// It returns the same errors as uplink.Project for invalid names and missing
+// buckets, objects and uploads. Listings are sorted by key and timestamps
+// come from the clock set with SetClock, so the results are deterministic.
+type Project struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	buckets  map[string]*bucket
+	uploads  map[string]*pendingUpload
+	uploadID int
+	failures map[string]error
+	closed   bool
+}
+
+type bucket struct {
+	created time.Time
+	objects map[string]*object
+}
+
+type object struct {
+	info uplink.Object
+	data []byte
+}
+
+// NewProject returns an empty in-memory project.
+func NewProject() *Project {
+	return &Project{
+		now:      time.Now,
+		buckets:  make(map[string]*bucket),
+		uploads:  make(map[string]*pendingUpload),
+		failures: make(map[string]error),
+	}
+}
+
+var _ uplink.ProjectAPI = (*Project)(nil)
+
+// SetClock sets the function that returns the creation time of buckets,
+// objects and uploads.
+func (project *Project) SetClock(now func() time.Time) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	project.now = now
+}
+
+// FailNext makes the next call of method, e.g. "UploadObject", fail with err.
+// Iterators return err from Err.
+func (project *Project) FailNext(method string, err error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	project.failures[method] = err
+}
+
+// begin is called by every method with project.mu held. It returns the error
+// that was set with FailNext for method or an error when the project was
+// closed.
+func (project *Project) begin(method string) error {
+	if project.closed {
+		return Error.New("project is closed")
+	}
+	if err, ok := project.failures[method]; ok {
+		delete(project.failures, method)
+		return err
+	}
+	return nil
+}
+
+// Close closes the project. Later calls fail.
+func (project *Project) Close() error {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("Close"); err != nil {
+		return err
+	}
+	project.closed = true
+	return nil
+}
+
+// Shutdown closes the project. Uploads in progress fail to commit.
+func (project *Project) Shutdown(ctx context.Context) error {
+	return project.Close()
+}
+
+// Capabilities returns the features supported by the in-memory project.
+func (project *Project) Capabilities(ctx context.Context) (*uplink.Capabilities, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("Capabilities"); err != nil {
+		return nil, err
+	}
+	return &uplink.Capabilities{
+		Versioning:     uplink.Unsupported,
+		ServerSideCopy: uplink.Supported,
+		ServerSideMove: uplink.Supported,
+		Multipart:      uplink.Supported,
+
+		LocalSegmentSize:             64 * memory.MiB.Int64(),
+		DefaultMaxCustomMetadataSize: uplink.MaxCustomMetadataSize,
+	}, nil
+}
+
+// RevokeAccess does nothing, because the in-memory project doesn't check
+// access grants.
+func (project *Project) RevokeAccess(ctx context.Context, access *uplink.Access) error {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("RevokeAccess"); err != nil {
+		return err
+	}
+	if access == nil {
+		return uplinkError.New("access grant is nil")
+	}
+	return nil
+}
+
+// StatBucket returns information about a bucket.
+func (project *Project) StatBucket(ctx context.Context, name string) (*uplink.Bucket, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("StatBucket"); err != nil {
+		return nil, err
+	}
+	b, err := project.bucket(name)
+	if err != nil {
+		return nil, err
+	}
+	return &uplink.Bucket{Name: name, Created: b.created}, nil
+}
+
+// CreateBucket creates a new bucket. When the bucket already exists, it
+// returns the existing bucket together with uplink.ErrBucketAlreadyExists.
+func (project *Project) CreateBucket(ctx context.Context, name string) (*uplink.Bucket, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("CreateBucket"); err != nil {
+		return nil, err
+	}
+	return project.createBucket(name)
+}
+
+func (project *Project) createBucket(name string) (*uplink.Bucket, error) {
+	if !validBucketName(name) {
+		return nil, errwrapf("%w (%q)", uplink.ErrBucketNameInvalid, name)
+	}
+	if b, ok := project.buckets[name]; ok {
+		return &uplink.Bucket{Name: name, Created: b.created}, errwrapf("%w (%q)", uplink.ErrBucketAlreadyExists, name)
+	}
+
+	b := &bucket{
+		created: project.now(),
+		objects: make(map[string]*object),
+	}
+	project.buckets[name] = b
+	return &uplink.Bucket{Name: name, Created: b.created}, nil
+}
+
+// EnsureBucket returns the bucket and creates it when it doesn't exist.
+func (project *Project) EnsureBucket(ctx context.Context, name string) (*uplink.Bucket, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("EnsureBucket"); err != nil {
+		return nil, err
+	}
+	return project.ensureBucket(name)
+}
+
+func (project *Project) ensureBucket(name string) (*uplink.Bucket, error) {
+	created, err := project.createBucket(name)
+	if errs.Is(err, uplink.ErrBucketAlreadyExists) {
+		return created, nil
+	}
+	return created, err
+}
+
+// DeleteBucket deletes an empty bucket.
+func (project *Project) DeleteBucket(ctx context.Context, name string) (*uplink.Bucket, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("DeleteBucket"); err != nil {
+		return nil, err
+	}
+	b, err := project.bucket(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.objects) > 0 {
+		return nil, errwrapf("%w (%q)", uplink.ErrBucketNotEmpty, name)
+	}
+	delete(project.buckets, name)
+	return &uplink.Bucket{Name: name, Created: b.created}, nil
+}
+
+// DeleteBucketWithObjects deletes a bucket and all its objects.
+func (project *Project) DeleteBucketWithObjects(ctx context.Context, name string) (*uplink.Bucket, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("DeleteBucketWithObjects"); err != nil {
+		return nil, err
+	}
+	b, err := project.bucket(name)
+	if err != nil {
+		return nil, err
+	}
+	delete(project.buckets, name)
+	for id, upload := range project.uploads {
+		if upload.bucket == name {
+			delete(project.uploads, id)
+		}
+	}
+	return &uplink.Bucket{Name: name, Created: b.created}, nil
+}
+
+// ListBuckets returns an iterator over the buckets, sorted by name.
+func (project *Project) ListBuckets(ctx context.Context, options *uplink.ListBucketsOptions) *uplink.BucketIterator {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("ListBuckets"); err != nil {
+		return expose.BucketIteratorNewLocal(nil, err)
+	}
+
+	var cursor string
+	if options != nil {
+		cursor = options.Cursor
+	}
+
+	var buckets []*uplink.Bucket
+	for name, b := range project.buckets {
+		if name > cursor {
+			buckets = append(buckets, &uplink.Bucket{Name: name, Created: b.created})
+		}
+	}
+	sort.Slice(buckets, func(i, k int) bool { return buckets[i].Name < buckets[k].Name })
+	return expose.BucketIteratorNewLocal(buckets, nil)
+}
+
+// ExportBucketMetadata returns the configuration of a bucket, and when
+// requested the metadata of all its objects.
+func (project *Project) ExportBucketMetadata(ctx context.Context, name string, options *uplink.ExportBucketOptions) (*uplink.BucketExport, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("ExportBucketMetadata"); err != nil {
+		return nil, err
+	}
+	b, err := project.bucket(name)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &uplink.BucketExport{
+		Version: 1,
+		Name:    name,
+		Created: b.created,
+	}
+	if options == nil || !options.IncludeObjects {
+		return export, nil
+	}
+
+	for _, key := range sortedKeys(b.objects) {
+		info := b.objects[key].info
+		export.Objects = append(export.Objects, uplink.ExportedObject{
+			Key:           key,
+			Created:       info.System.Created,
+			Expires:       info.System.Expires,
+			ContentLength: info.System.ContentLength,
+			Custom:        info.Custom.Clone(),
+		})
+	}
+	return export, nil
+}
+
+// ImportBucketMetadata creates the bucket described by export. When
+// options.Source is set, the exported objects are downloaded from it.
+func (project *Project) ImportBucketMetadata(ctx context.Context, export *uplink.BucketExport, options *uplink.ImportBucketOptions) (_ *uplink.Bucket, err error) {
+	if options == nil {
+		options = &uplink.ImportBucketOptions{}
+	}
+	if export == nil {
+		return nil, uplinkError.New("bucket export is nil")
+	}
+
+	name := options.Bucket
+	if name == "" {
+		name = export.Name
+	}
+
+	project.mu.Lock()
+	if err := project.begin("ImportBucketMetadata"); err != nil {
+		project.mu.Unlock()
+		return nil, err
+	}
+	created, err := project.ensureBucket(name)
+	project.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Source == nil {
+		return created, nil
+	}
+	for _, exported := range export.Objects {
+		data, err := download(ctx, options.Source, export.Name, exported.Key)
+		if errs.Is(err, uplink.ErrObjectNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		project.mu.Lock()
+		err = project.putObject(name, uplink.Object{
+			Key: exported.Key,
+			System: uplink.SystemMetadata{
+				Expires: exported.Expires,
+			},
+			Custom: exported.Custom.Clone(),
+		}, data)
+		project.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return created, nil
+}
+
+// download reads the whole object from source.
+func download(ctx context.Context, source uplink.ProjectAPI, bucket, key string) (_ []byte, err error) {
+	download, err := source.DownloadObject(ctx, bucket, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = errs.Combine(err, download.Close()) }()
+
+	return io.ReadAll(download)
+}
+
+// StatObject returns information about an object.
+func (project *Project) StatObject(ctx context.Context, bucket, key string) (*uplink.Object, error) {
+	return project.StatObjectWithOptions(ctx, bucket, key, nil)
+}
+
+// StatObjectWithOptions returns information about an object, like
+// StatObject. The options have no effect on the in-memory project.
+func (project *Project) StatObjectWithOptions(ctx context.Context, bucket, key string, options *uplink.StatObjectOptions) (*uplink.Object, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("StatObject"); err != nil {
+		return nil, err
+	}
+	obj, err := project.object(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return obj.clone(), nil
+}
+
+// StatObjects returns information about the objects at keys.
+func (project *Project) StatObjects(ctx context.Context, bucket string, keys []string) ([]uplink.StatObjectResult, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("StatObjects"); err != nil {
+		return nil, err
+	}
+	if _, err := project.bucket(bucket); err != nil {
+		return nil, err
+	}
+
+	results := make([]uplink.StatObjectResult, 0, len(keys))
+	for _, key := range keys {
+		result := uplink.StatObjectResult{Key: key}
+		obj, err := project.object(bucket, key)
+		if err != nil {
+			result.Err = err
+		} else {
+			result.Object = obj.clone()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UploadObject starts an upload to an object. The object is stored when the
+// upload is committed.
+func (project *Project) UploadObject(ctx context.Context, bucket, key string, options *uplink.UploadOptions) (*uplink.Upload, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("UploadObject"); err != nil {
+		return nil, err
+	}
+	if _, err := project.bucket(bucket); err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectKeyInvalid, key)
+	}
+
+	info := &uplink.Object{
+		Key:    key,
+		Custom: uplink.CustomMetadata{},
+	}
+	if options != nil {
+		info.System.Expires = options.Expires
+	}
+
+	return expose.UploadNewLocal(ctx, bucket, info, func(data []byte, info *uplink.Object) (time.Time, error) {
+		project.mu.Lock()
+		defer project.mu.Unlock()
+
+		if project.closed {
+			return time.Time{}, Error.New("project is closed")
+		}
+		if err := project.putObject(bucket, *info, data); err != nil {
+			return time.Time{}, err
+		}
+		return project.buckets[bucket].objects[key].info.System.Created, nil
+	}), nil
+}
+
+// putObject stores an object with data, replacing an existing one.
+func (project *Project) putObject(bucket string, info uplink.Object, data []byte) error {
+	b, err := project.bucket(bucket)
+	if err != nil {
+		return err
+	}
+
+	info.System.Created = project.now()
+	info.System.ContentLength = int64(len(data))
+	info.Custom = info.Custom.Clone()
+
+	b.objects[info.Key] = &object{
+		info: info,
+		data: append([]byte(nil), data...),
+	}
+	return nil
+}
+
+// DownloadObject starts a download from an object.
+func (project *Project) DownloadObject(ctx context.Context, bucket, key string, options *uplink.DownloadOptions) (*uplink.Download, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("DownloadObject"); err != nil {
+		return nil, err
+	}
+	return project.download(ctx, bucket, key, options)
+}
+
+// DownloadObjectWithInfo starts a download of object, which was returned by
+// StatObject.
+func (project *Project) DownloadObjectWithInfo(ctx context.Context, bucket string, object *uplink.Object, options *uplink.DownloadOptions) (*uplink.Download, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("DownloadObjectWithInfo"); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, uplinkError.New("object is nil")
+	}
+	return project.download(ctx, bucket, object.Key, options)
+}
+
+func (project *Project) download(ctx context.Context, bucket, key string, options *uplink.DownloadOptions) (*uplink.Download, error) {
+	obj, err := project.object(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(len(obj.data))
+	start, limit := int64(0), size
+	switch {
+	case options == nil:
+	case options.Offset < 0:
+		if options.Length >= 0 {
+			return nil, uplinkError.New("suffix requires length to be negative, got %v", options.Length)
+		}
+		start = size + options.Offset
+	case options.Length < 0:
+		start = options.Offset
+	default:
+		start = options.Offset
+		limit = options.Offset + options.Length
+	}
+	if start < 0 {
+		start = 0
+	}
+	if limit > size {
+		limit = size
+	}
+	if start > limit {
+		start = limit
+	}
+
+	return expose.DownloadNewLocal(ctx, bucket, obj.clone(), obj.data[start:limit]), nil
+}
+
+// DeleteObject deletes an object. Like the satellite, it returns nil
+// without an error when the object doesn't exist.
+func (project *Project) DeleteObject(ctx context.Context, bucket, key string) (*uplink.Object, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("DeleteObject"); err != nil {
+		return nil, err
+	}
+	obj, err := project.object(bucket, key)
+	switch {
+	case errs.Is(err, uplink.ErrObjectNotFound), errs.Is(err, uplink.ErrBucketNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	delete(project.buckets[bucket].objects, key)
+	return obj.clone(), nil
+}
+
+// DeletePrefix deletes all objects under prefix in bucket.
+func (project *Project) DeletePrefix(ctx context.Context, bucket, prefix string, options *uplink.DeletePrefixOptions) (*uplink.DeletePrefixResult, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	result := &uplink.DeletePrefixResult{}
+	if err := project.begin("DeletePrefix"); err != nil {
+		return result, err
+	}
+	b, err := project.bucket(bucket)
+	if err != nil {
+		return result, err
+	}
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(b.objects, key)
+			result.Deleted++
+		}
+	}
+	if options != nil && options.Progress != nil {
+		options.Progress(result.Deleted, 0)
+	}
+	return result, nil
+}
+
+// UpdateObjectMetadata replaces the custom metadata of an object.
+func (project *Project) UpdateObjectMetadata(ctx context.Context, bucket, key string, newMetadata uplink.CustomMetadata, options *uplink.UploadObjectMetadataOptions) error {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("UpdateObjectMetadata"); err != nil {
+		return err
+	}
+	obj, err := project.object(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := newMetadata.Verify(); err != nil {
+		return uplinkError.Wrap(err)
+	}
+	obj.info.Custom = newMetadata.Clone()
+	return nil
+}
+
+// CopyObject copies an object to a different bucket or key.
+func (project *Project) CopyObject(ctx context.Context, oldBucket, oldKey, newBucket, newKey string, options *uplink.CopyObjectOptions) (*uplink.Object, error) {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("CopyObject"); err != nil {
+		return nil, err
+	}
+	obj, err := project.copyObject(oldBucket, oldKey, newBucket, newKey)
+	if err != nil {
+		return nil, err
+	}
+	return obj.clone(), nil
+}
+
+// MoveObject moves an object to a different bucket or key.
+func (project *Project) MoveObject(ctx context.Context, oldBucket, oldKey, newBucket, newKey string, options *uplink.MoveObjectOptions) error {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("MoveObject"); err != nil {
+		return err
+	}
+	if _, err := project.copyObject(oldBucket, oldKey, newBucket, newKey); err != nil {
+		return err
+	}
+	if oldBucket != newBucket || oldKey != newKey {
+		delete(project.buckets[oldBucket].objects, oldKey)
+	}
+	return nil
+}
+
+func (project *Project) copyObject(oldBucket, oldKey, newBucket, newKey string) (*object, error) {
+	source, err := project.object(oldBucket, oldKey)
+	if err != nil {
+		return nil, err
+	}
+	target, err := project.bucket(newBucket)
+	if err != nil {
+		return nil, err
+	}
+	if newKey == "" {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectKeyInvalid, newKey)
+	}
+
+	info := source.info
+	info.Key = newKey
+	info.Custom = source.info.Custom.Clone()
+	copied := &object{info: info, data: source.data}
+	target.objects[newKey] = copied
+	return copied, nil
+}
+
+// ListObjects returns an iterator over the objects, sorted by key.
+func (project *Project) ListObjects(ctx context.Context, bucket string, options *uplink.ListObjectsOptions) *uplink.ObjectIterator {
+	project.mu.Lock()
+	defer project.mu.Unlock()
+
+	if err := project.begin("ListObjects"); err != nil {
+		return expose.ObjectIteratorNewLocal(nil, options, err)
+	}
+	b, err := project.bucket(bucket)
+	if err != nil {
+		return expose.ObjectIteratorNewLocal(nil, options, err)
+	}
+
+	opts := uplink.ListObjectsOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Prefix != "" && !strings.HasSuffix(opts.Prefix, "/") {
+		return expose.ObjectIteratorNewLocal(nil, options, uplinkError.New("prefix should end with slash"))
+	}
+
+	var objects []*uplink.Object
+	for _, entry := range list(sortedKeys(b.objects), opts.Prefix, opts.Cursor, opts.Recursive) {
+		if entry.isPrefix {
+			objects = append(objects, &uplink.Object{Key: entry.key, IsPrefix: true})
+			continue
+		}
+		objects = append(objects, b.objects[entry.key].clone())
+	}
+	return expose.ObjectIteratorNewLocal(objects, options, nil)
+}
+
+// listEntry is an item of a listing.
+type listEntry struct {
+	key      string
+	isPrefix bool
+}
+
+// list returns the sorted keys under prefix after cursor. When recursive is
+// false, the keys are collapsed into prefixes at the next slash.
+func list(keys []string, prefix, cursor string, recursive bool) []listEntry {
+	cursor = strings.TrimPrefix(cursor, prefix)
+
+	var entries []listEntry
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rel := key[len(prefix):]
+		if rel <= cursor {
+			continue
+		}
+
+		if !recursive {
+			if i := strings.IndexByte(rel, '/'); i >= 0 {
+				collapsed := prefix + rel[:i+1]
+				if len(entries) == 0 || entries[len(entries)-1].key != collapsed {
+					entries = append(entries, listEntry{key: collapsed, isPrefix: true})
+				}
+				continue
+			}
+		}
+		entries = append(entries, listEntry{key: key})
+	}
+	return entries
+}
+
+// bucket returns the bucket with name.
+func (project *Project) bucket(name string) (*bucket, error) {
+	if name == "" {
+		return nil, errwrapf("%w (%q)", uplink.ErrBucketNameInvalid, name)
+	}
+	b, ok := project.buckets[name]
+	if !ok {
+		return nil, errwrapf("%w (%q)", uplink.ErrBucketNotFound, name)
+	}
+	return b, nil
+}
+
+// object returns the object at key in bucket.
+func (project *Project) object(bucket, key string) (*object, error) {
+	b, err := project.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectKeyInvalid, key)
+	}
+	obj, ok := b.objects[key]
+	if !ok {
+		return nil, errwrapf("%w (%q)", uplink.ErrObjectNotFound, key)
+	}
+	return obj, nil
+}
+
+// clone returns a copy of the object information, so callers can't modify
+// the stored object.
+func (obj *object) clone() *uplink.Object {
+	info := obj.info
+	info.Custom = obj.info.Custom.Clone()
+	return &info
+}
+
+// validBucketName returns whether name follows the bucket naming rules of
+// the satellite.
+func validBucketName(name string) bool {
+	if len(name) < 3 || len(name) > 63 {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case (r == '-' || r == '.') && i > 0 && i < len(name)-1:
+		default:
+			return false
+		}
+	}
+	return !strings.Contains(name, "..")
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func errwrapf(format string, err error, args ...interface{}) error {
+	return uplinkError.Wrap(fmt.Errorf(format, append([]interface{}{err}, args...)...))
+}
@@ -0,0 +1,217 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplinktest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/uplink"
+	"storj.io/uplink/uplinktest"
+)
+
+func upload(t *testing.T, project uplink.ProjectAPI, bucket, key string, data []byte, custom uplink.CustomMetadata) {
+	ctx := context.Background()
+
+	upload, err := project.UploadObject(ctx, bucket, key, nil)
+	require.NoError(t, err)
+	_, err = upload.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, upload.SetCustomMetadata(ctx, custom))
+	require.NoError(t, upload.Commit())
+}
+
+func download(t *testing.T, project uplink.ProjectAPI, bucket, key string, options *uplink.DownloadOptions) []byte {
+	download, err := project.DownloadObject(context.Background(), bucket, key, options)
+	require.NoError(t, err)
+	data, err := io.ReadAll(download)
+	require.NoError(t, err)
+	require.NoError(t, download.Close())
+	return data
+}
+
+func listKeys(t *testing.T, objects *uplink.ObjectIterator) []string {
+	var keys []string
+	for objects.Next() {
+		keys = append(keys, objects.Item().Key)
+	}
+	require.NoError(t, objects.Err())
+	return keys
+}
+
+func TestProject_Objects(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	project := uplinktest.NewProject()
+	project.SetClock(func() time.Time { return now })
+
+	_, err := project.UploadObject(ctx, "missing", "key", nil)
+	require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+	_, err = project.CreateBucket(ctx, "Invalid_Name")
+	require.ErrorIs(t, err, uplink.ErrBucketNameInvalid)
+
+	created, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+	require.Equal(t, now, created.Created)
+	_, err = project.CreateBucket(ctx, "bucket")
+	require.ErrorIs(t, err, uplink.ErrBucketAlreadyExists)
+
+	upload(t, project, "bucket", "a/1", []byte("hello world"), uplink.CustomMetadata{"k": "v"})
+	upload(t, project, "bucket", "a/2", []byte("2"), nil)
+	upload(t, project, "bucket", "b", []byte("b"), nil)
+
+	object, err := project.StatObject(ctx, "bucket", "a/1")
+	require.NoError(t, err)
+	require.Equal(t, "a/1", object.Key)
+	require.Equal(t, int64(11), object.System.ContentLength)
+	require.Equal(t, now, object.System.Created)
+	require.Equal(t, uplink.CustomMetadata{"k": "v"}, object.Custom)
+
+	_, err = project.StatObject(ctx, "bucket", "missing")
+	require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+
+	object, err = project.StatObjectWithOptions(ctx, "bucket", "a/1", &uplink.StatObjectOptions{Timeout: time.Minute})
+	require.NoError(t, err)
+	require.Equal(t, "a/1", object.Key)
+
+	require.Equal(t, []byte("hello world"), download(t, project, "bucket", "a/1", nil))
+	require.Equal(t, []byte("world"), download(t, project, "bucket", "a/1", &uplink.DownloadOptions{Offset: -5, Length: -1}))
+	require.Equal(t, []byte("lo w"), download(t, project, "bucket", "a/1", &uplink.DownloadOptions{Offset: 3, Length: 4}))
+
+	require.Equal(t, []string{"a/", "b"}, listKeys(t, project.ListObjects(ctx, "bucket", nil)))
+	require.Equal(t, []string{"a/1", "a/2", "b"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{Recursive: true})))
+	require.Equal(t, []string{"a/2"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{Prefix: "a/", Cursor: "1"})))
+	require.Equal(t, []string{"a/1"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{
+		Recursive:            true,
+		CustomMetadataFilter: map[string]string{"k": "v"},
+	})))
+
+	require.NoError(t, project.MoveObject(ctx, "bucket", "b", "bucket", "c", nil))
+	_, err = project.CopyObject(ctx, "bucket", "c", "bucket", "d", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/1", "a/2", "c", "d"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{Recursive: true})))
+
+	_, err = project.DeleteBucket(ctx, "bucket")
+	require.ErrorIs(t, err, uplink.ErrBucketNotEmpty)
+
+	result, err := project.DeletePrefix(ctx, "bucket", "a/", nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, result.Deleted)
+
+	deleted, err := project.DeleteObject(ctx, "bucket", "c")
+	require.NoError(t, err)
+	require.Equal(t, "c", deleted.Key)
+	deleted, err = project.DeleteObject(ctx, "bucket", "c")
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+
+	_, err = project.DeleteBucketWithObjects(ctx, "bucket")
+	require.NoError(t, err)
+	_, err = project.StatBucket(ctx, "bucket")
+	require.ErrorIs(t, err, uplink.ErrBucketNotFound)
+}
+
+func TestProject_Multipart(t *testing.T) {
+	ctx := context.Background()
+	project := uplinktest.NewProject()
+
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	info, err := project.BeginUpload(ctx, "bucket", "key", nil)
+	require.NoError(t, err)
+
+	for _, number := range []uint32{2, 1} {
+		part, err := project.UploadPart(ctx, "bucket", "key", info.UploadID, number)
+		require.NoError(t, err)
+		_, err = part.Write([]byte{byte('0' + number)})
+		require.NoError(t, err)
+		require.NoError(t, part.SetETag([]byte("etag")))
+		require.NoError(t, part.Commit())
+	}
+
+	parts := project.ListUploadParts(ctx, "bucket", "key", info.UploadID, nil)
+	var numbers []uint32
+	for parts.Next() {
+		require.Equal(t, []byte("etag"), parts.Item().ETag)
+		numbers = append(numbers, parts.Item().PartNumber)
+	}
+	require.NoError(t, parts.Err())
+	require.Equal(t, []uint32{1, 2}, numbers)
+
+	uploads := project.ListUploads(ctx, "bucket", nil)
+	require.True(t, uploads.Next())
+	require.Equal(t, info.UploadID, uploads.Item().UploadID)
+	require.False(t, uploads.Next())
+	require.NoError(t, uploads.Err())
+
+	object, err := project.CommitUpload(ctx, "bucket", "key", info.UploadID, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, object.System.ContentLength)
+	require.Equal(t, []byte("12"), download(t, project, "bucket", "key", nil))
+
+	err = project.AbortUpload(ctx, "bucket", "key", info.UploadID)
+	require.ErrorIs(t, err, uplink.ErrObjectNotFound)
+	err = project.AbortUpload(ctx, "bucket", "key", "invalid")
+	require.ErrorIs(t, err, uplink.ErrUploadIDInvalid)
+
+	parts = project.ListUploadParts(ctx, "bucket", "key", info.UploadID, nil)
+	require.False(t, parts.Next())
+	require.ErrorIs(t, parts.Err(), uplink.ErrObjectNotFound)
+}
+
+func TestProject_FailNext(t *testing.T) {
+	ctx := context.Background()
+	project := uplinktest.NewProject()
+
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+
+	injected := errors.New("injected")
+	project.FailNext("ListObjects", injected)
+
+	objects := project.ListObjects(ctx, "bucket", nil)
+	require.False(t, objects.Next())
+	require.ErrorIs(t, objects.Err(), injected)
+
+	// only the next call fails.
+	objects = project.ListObjects(ctx, "bucket", nil)
+	require.False(t, objects.Next())
+	require.NoError(t, objects.Err())
+
+	buckets := project.ListBuckets(ctx, nil)
+	require.True(t, buckets.Next())
+	require.Equal(t, "bucket", buckets.Item().Name)
+	require.False(t, buckets.Next())
+
+	require.NoError(t, project.Close())
+	_, err = project.StatBucket(ctx, "bucket")
+	require.Error(t, err)
+}
+
+func TestProject_SortBy(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	project := uplinktest.NewProject()
+	project.SetClock(func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	})
+
+	_, err := project.CreateBucket(ctx, "bucket")
+	require.NoError(t, err)
+	upload(t, project, "bucket", "b", []byte("bb"), nil)
+	upload(t, project, "bucket", "a", []byte("aaa"), nil)
+	upload(t, project, "bucket", "c", []byte("c"), nil)
+
+	require.Equal(t, []string{"b", "a", "c"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{SortBy: uplink.SortByCreated})))
+	require.Equal(t, []string{"a", "b", "c"}, listKeys(t, project.ListObjects(ctx, "bucket", &uplink.ListObjectsOptions{SortBy: uplink.SortBySize, SortDescending: true})))
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package uplinkverify verifies pieces offline against the signatures of the
+// satellite, the storage node and the uplink that uploaded them.
+//
+// When a piece is downloaded, the storage node returns the order limit, which
+// was signed by the satellite when the piece was uploaded, and the piece
+// hash it signed at the end of the upload. Together with the piece data they
+// prove which satellite authorized the piece and what the storage node
+// stored, without contacting either of them.
+package uplinkverify
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/spacemonkeygo/monkit/v3"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/pb"
+	"storj.io/common/signing"
+)
+
+var mon = monkit.Package()
+
+// Error is the error class for failed verifications.
+var Error = errs.Class("uplinkverify")
+
+// Piece is a piece with the proofs of its authenticity.
+type Piece struct {
+	// Data is the content of the piece, without the piece header.
+	Data []byte
+	// Limit is the order limit for uploading the piece, signed by the
+	// satellite.
+	Limit *pb.OrderLimit
+	// Hash is the hash of the piece, signed by the storage node or, for
+	// VerifyUplink, by the uplink.
+	Hash *pb.PieceHash
+}
+
+// Verify verifies that the satellite signed piece.Limit, that the storage
+// node named in the limit signed piece.Hash and that piece.Data matches the
+// hash.
+//
+// Signees for identities are created with signing.SigneeFromPeerIdentity.
+func Verify(ctx context.Context, satellite, storageNode signing.Signee, piece Piece) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := verifyLimit(ctx, satellite, piece); err != nil {
+		return err
+	}
+	if piece.Limit.StorageNodeId != storageNode.ID() {
+		return Error.New("order limit is for storage node %s, not %s", piece.Limit.StorageNodeId, storageNode.ID())
+	}
+	if err := signing.VerifyPieceHashSignature(ctx, storageNode, piece.Hash); err != nil {
+		return Error.New("invalid storage node signature: %v", err)
+	}
+	return verifyData(piece)
+}
+
+// VerifyUplink verifies that the satellite signed piece.Limit, that the
+// uplink, whose public key is in the limit, signed piece.Hash and that
+// piece.Data matches the hash.
+func VerifyUplink(ctx context.Context, satellite signing.Signee, piece Piece) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err := verifyLimit(ctx, satellite, piece); err != nil {
+		return err
+	}
+	if err := signing.VerifyUplinkPieceHashSignature(ctx, piece.Limit.UplinkPublicKey, piece.Hash); err != nil {
+		return Error.New("invalid uplink signature: %v", err)
+	}
+	return verifyData(piece)
+}
+
+// verifyLimit verifies the satellite signature of the order limit and that
+// the hash is for the piece in the limit.
+func verifyLimit(ctx context.Context, satellite signing.Signee, piece Piece) error {
+	switch {
+	case piece.Limit == nil:
+		return Error.New("order limit is missing")
+	case piece.Hash == nil:
+		return Error.New("piece hash is missing")
+	case piece.Limit.SatelliteId != satellite.ID():
+		return Error.New("order limit is from satellite %s, not %s", piece.Limit.SatelliteId, satellite.ID())
+	case piece.Limit.PieceId != piece.Hash.PieceId:
+		return Error.New("piece hash is for piece %s, not %s", piece.Hash.PieceId, piece.Limit.PieceId)
+	}
+	if err := signing.VerifyOrderLimitSignature(ctx, satellite, piece.Limit); err != nil {
+		return Error.New("invalid satellite signature: %v", err)
+	}
+	return nil
+}
+
+// verifyData verifies that the data matches the piece hash.
+func verifyData(piece Piece) error {
+	if piece.Hash.PieceSize != 0 && piece.Hash.PieceSize != int64(len(piece.Data)) {
+		return Error.New("piece size is %d, expected %d", len(piece.Data), piece.Hash.PieceSize)
+	}
+	if piece.Limit.Limit > 0 && int64(len(piece.Data)) > piece.Limit.Limit {
+		return Error.New("piece size %d exceeds the order limit of %d", len(piece.Data), piece.Limit.Limit)
+	}
+
+	hasher := pb.NewHashFromAlgorithm(piece.Hash.HashAlgorithm)
+	_, _ = hasher.Write(piece.Data)
+	if !bytes.Equal(hasher.Sum(nil), piece.Hash.Hash) {
+		return Error.New("piece data doesn't match the %s hash", piece.Hash.HashAlgorithm)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplinkverify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"storj.io/common/identity/testidentity"
+	"storj.io/common/pb"
+	"storj.io/common/signing"
+	"storj.io/common/storj"
+	"storj.io/common/testrand"
+	"storj.io/uplink/uplinkverify"
+)
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+
+	satellite := testidentity.MustPregeneratedSignedIdentity(0, storj.LatestIDVersion())
+	storageNode := testidentity.MustPregeneratedSignedIdentity(1, storj.LatestIDVersion())
+	other := testidentity.MustPregeneratedSignedIdentity(2, storj.LatestIDVersion())
+
+	uplinkPublic, uplinkPrivate, err := storj.NewPieceKey()
+	require.NoError(t, err)
+
+	data := testrand.BytesInt(1000)
+	limit, err := signing.SignOrderLimit(ctx, signing.SignerFromFullIdentity(satellite), &pb.OrderLimit{
+		SerialNumber:    testrand.SerialNumber(),
+		SatelliteId:     satellite.ID,
+		UplinkPublicKey: uplinkPublic,
+		StorageNodeId:   storageNode.ID,
+		PieceId:         testrand.PieceID(),
+		Limit:           2000,
+		Action:          pb.PieceAction_PUT,
+		OrderCreation:   time.Now(),
+		OrderExpiration: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	hash := func(data []byte) *pb.PieceHash {
+		hasher := pb.NewHashFromAlgorithm(pb.PieceHashAlgorithm_BLAKE3)
+		_, _ = hasher.Write(data)
+		return &pb.PieceHash{
+			PieceId:       limit.PieceId,
+			Hash:          hasher.Sum(nil),
+			PieceSize:     int64(len(data)),
+			Timestamp:     time.Now(),
+			HashAlgorithm: pb.PieceHashAlgorithm_BLAKE3,
+		}
+	}
+
+	nodeHash, err := signing.SignPieceHash(ctx, signing.SignerFromFullIdentity(storageNode), hash(data))
+	require.NoError(t, err)
+	uplinkHash, err := signing.SignUplinkPieceHash(ctx, uplinkPrivate, hash(data))
+	require.NoError(t, err)
+
+	satelliteSignee := signing.SigneeFromPeerIdentity(satellite.PeerIdentity())
+	nodeSignee := signing.SigneeFromPeerIdentity(storageNode.PeerIdentity())
+	otherSignee := signing.SigneeFromPeerIdentity(other.PeerIdentity())
+
+	piece := uplinkverify.Piece{Data: data, Limit: limit, Hash: nodeHash}
+	require.NoError(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, piece))
+	require.NoError(t, uplinkverify.VerifyUplink(ctx, satelliteSignee, uplinkverify.Piece{Data: data, Limit: limit, Hash: uplinkHash}))
+
+	// wrong signers.
+	require.Error(t, uplinkverify.Verify(ctx, otherSignee, nodeSignee, piece))
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, otherSignee, piece))
+	require.Error(t, uplinkverify.VerifyUplink(ctx, satelliteSignee, piece))
+
+	// modified data.
+	modified := append([]byte(nil), data...)
+	modified[0]++
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: modified, Limit: limit, Hash: nodeHash}))
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: data[1:], Limit: limit, Hash: nodeHash}))
+
+	// modified hash.
+	forged := *nodeHash
+	forged.Hash = hash(modified).Hash
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: modified, Limit: limit, Hash: &forged}))
+
+	// modified limit.
+	changed := *limit
+	changed.Limit = 10000
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: data, Limit: &changed, Hash: nodeHash}))
+
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: data, Hash: nodeHash}))
+	require.Error(t, uplinkverify.Verify(ctx, satelliteSignee, nodeSignee, uplinkverify.Piece{Data: data, Limit: limit}))
+}
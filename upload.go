@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"os"
 	"runtime"
 	"sync"
 	"time"
@@ -16,7 +17,8 @@ import (
 	"storj.io/common/leak"
 	"storj.io/common/pb"
 	"storj.io/eventkit"
-	"storj.io/uplink/private/eestream/scheduler"
+	"storj.io/uplink/private/metaclient"
+	"storj.io/uplink/private/stall"
 	"storj.io/uplink/private/storage/streams"
 	"storj.io/uplink/private/stream"
 )
@@ -24,12 +26,93 @@ import (
 // ErrUploadDone is returned when either Abort or Commit has already been called.
 var ErrUploadDone = errors.New("upload done")
 
+// ErrUploadStalled is returned when no storage node acknowledged a piece
+// within UploadOptions.StallTimeout.
+var ErrUploadStalled = errors.New("upload stalled")
+
 // UploadOptions contains additional options for uploading.
 type UploadOptions struct {
 	// When Expires is zero, there is no expiration.
 	Expires time.Time
+
+	// Compression compresses the object data before encryption. When the
+	// beginning of the data turns out to be incompressible, the data is
+	// stored as is. Compressed objects are marked with
+	// CompressionMetadataKey in their custom metadata and are decompressed
+	// transparently by DownloadObject. Compressed objects can only be
+	// downloaded as a whole. ContentLength of a compressed object is the
+	// compressed size.
+	Compression Compression
+
+	// ContentLength is the expected size of the object in bytes, if known.
+	// It is only a hint used to size the buffers of ReadFrom and, for
+	// objects larger than the maximum segment size, to split the object
+	// into equally sized segments. The object is not required to have this
+	// size.
+	// Zero means unknown.
+	ContentLength int64
+
+	// StallTimeout fails the upload with ErrUploadStalled when no storage
+	// node acknowledged a piece for this long while pieces are being
+	// uploaded. The error lists the storage nodes that didn't respond.
+	// Zero means no stall detection.
+	StallTimeout time.Duration
+
+	// SpoolDir enables writing a copy of the uploaded data to a temporary
+	// file in SpoolDir. When the upload fails, Upload.Spool returns the data
+	// written so far, so the upload can be retried without re-reading a
+	// source that can't be read again. The file is removed by Commit and
+	// Abort, unless KeepSpool is set.
+	// If SpoolDir is empty, the data is not spooled.
+	SpoolDir string
+
+	// KeepSpool keeps the spool file after Abort or a failed Commit, so it
+	// can be retrieved with Upload.Spool. The file is then only removed when
+	// the reader returned by Spool is closed, so Spool must be called.
+	KeepSpool bool
+
+	// PieceHash selects the algorithm used to hash the uploaded pieces.
+	PieceHash PieceHash
+
+	// EncryptionBlockSize is the size of the blocks the data is encrypted
+	// in. Smaller blocks make ranged downloads decrypt less data around
+	// the range, larger blocks have less overhead. It must be larger than
+	// the 16 bytes the cipher adds to every block and must not be larger
+	// than the segment size. Multipart uploads always use the default.
+	// Zero means the default of 7424 bytes.
+	EncryptionBlockSize int32
+
+	// Timeout overrides Config.MetainfoTimeout for the requests to the
+	// satellite of this upload. Uploads to storage nodes are not affected.
+	Timeout time.Duration
+}
+
+// cipherOverhead is the number of bytes AES-GCM and SecretBox add to every
+// encrypted block.
+const cipherOverhead = 16
+
+// verify verifies the options for uploads with segments of segmentSize.
+func (options *UploadOptions) verify(segmentSize int64) error {
+	if err := options.Compression.validate(); err != nil {
+		return err
+	}
+	if options.EncryptionBlockSize != 0 {
+		if options.EncryptionBlockSize <= cipherOverhead || int64(options.EncryptionBlockSize) > segmentSize {
+			return packageError.New("invalid encryption block size %d", options.EncryptionBlockSize)
+		}
+	}
+	return nil
 }
 
+// readFromBufferSize is the buffer size ReadFrom uses when the content length
+// is unknown.
+const readFromBufferSize = 256 * 1024
+
+// readFromMinBufferSize is the smallest buffer size ReadFrom starts with, so
+// a content length that is too small doesn't make it read a few bytes at a
+// time.
+const readFromMinBufferSize = 32 * 1024
+
 // UploadObject starts an upload to the specific key.
 //
 // It is not guaranteed that the uncommitted object is visible through ListUploads while uploading.
@@ -38,7 +121,14 @@ func (project *Project) UploadObject(ctx context.Context, bucket, key string, op
 		bucket: bucket,
 		stats:  newOperationStats(ctx, project.access.satelliteURL),
 	}
+	if options != nil {
+		upload.contentLength = options.ContentLength
+		if options.Timeout != 0 {
+			ctx = metaclient.WithTimeout(ctx, options.Timeout)
+		}
+	}
 	upload.task = mon.TaskNamed("Upload")(&ctx)
+	upload.observe = project.instrument("UploadObject")
 	defer func() {
 		if err != nil {
 			upload.stats.flagFailure(err)
@@ -54,10 +144,38 @@ func (project *Project) UploadObject(ctx context.Context, bucket, key string, op
 	if key == "" {
 		return nil, errwrapf("%w (%q)", ErrObjectKeyInvalid, key)
 	}
+	if err := project.inflight.err(); err != nil {
+		return nil, err
+	}
 
 	if options == nil {
 		options = &UploadOptions{}
 	}
+	if err := options.verify(project.segmentSize); err != nil {
+		return nil, err
+	}
+	ctx, err = options.PieceHash.withPieceHash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptionParameters := project.encryptionParameters
+	if options.EncryptionBlockSize != 0 {
+		encryptionParameters.BlockSize = options.EncryptionBlockSize
+	}
+
+	if options.SpoolDir != "" {
+		upload.spool, err = os.CreateTemp(options.SpoolDir, "uplink-spool-*")
+		if err != nil {
+			return nil, packageError.Wrap(err)
+		}
+		upload.keepSpool = options.KeepSpool
+		defer func() {
+			if err != nil {
+				upload.removeSpool()
+			}
+		}()
+	}
 
 	// N.B. we always call dbCleanup which closes the db because
 	// closing it earlier has the benefit of returning a connection to
@@ -79,7 +197,17 @@ func (project *Project) UploadObject(ctx context.Context, bucket, key string, op
 	ctx, cancel := context.WithCancel(ctx)
 
 	upload.cancel = cancel
+	if options.StallTimeout > 0 {
+		upload.stall = stall.New(options.StallTimeout, cancel)
+		ctx = stall.WithDetector(ctx, upload.stall)
+		defer func() {
+			if err != nil {
+				upload.stall.Close()
+			}
+		}()
+	}
 	upload.object = convertObject(&info)
+	upload.object.System.Expires = options.Expires
 
 	meta := dynamicMetadata{upload.object}
 	mutableStream, err := obj.CreateDynamicStream(ctx, meta, options.Expires)
@@ -97,16 +225,24 @@ func (project *Project) UploadObject(ctx context.Context, bucket, key string, op
 		upload.stats.encPath = encPath
 	}
 
-	streams, err := project.getStreamsStore(ctx)
+	// the compressed size isn't known up front, so compressed objects use
+	// the default segment size.
+	segmentSize := project.segmentSize
+	if options.Compression == CompressionNone {
+		segmentSize = streams.SegmentSizeFor(segmentSize, options.ContentLength, int64(encryptionParameters.BlockSize))
+	}
+
+	streams, err := project.getStreamsStoreWith(ctx, segmentSize, encryptionParameters)
 	if err != nil {
 		return nil, convertKnownErrors(err, bucket, key)
 	}
 	upload.streams = streams
+	upload.recentStats = project.recentStats
 
 	if project.concurrentSegmentUploadConfig == nil {
 		upload.upload = stream.NewUpload(ctx, mutableStream, streams)
 	} else {
-		sched := scheduler.New(project.concurrentSegmentUploadConfig.SchedulerOptions)
+		sched := project.uploadScheduler()
 		u, err := streams.UploadObject(ctx, mutableStream.BucketName(), mutableStream.Path(), mutableStream, mutableStream.Expires(), sched)
 		if err != nil {
 			return nil, convertKnownErrors(err, bucket, key)
@@ -114,7 +250,22 @@ func (project *Project) UploadObject(ctx context.Context, bucket, key string, op
 		upload.upload = u
 	}
 
+	if options.Compression != CompressionNone {
+		upload.compress = newCompressWriter(options.Compression, upload.upload)
+	}
+
 	upload.tracker = project.tracker.Child("upload", 1)
+
+	upload.mu.Lock()
+	upload.untrack, err = project.inflight.add(inflightOp{
+		cancel: upload.cancel,
+		abort:  upload.Abort,
+	})
+	upload.mu.Unlock()
+	if err != nil {
+		upload.cancel()
+		return nil, errs.Combine(err, upload.upload.Abort(), upload.streams.Close(), upload.tracker.Close())
+	}
 	return upload, nil
 }
 
@@ -144,13 +295,29 @@ type Upload struct {
 	object  *Object
 	streams *streams.Store
 
-	stats operationStats
-	task  func(*error)
+	compress      *compressWriter
+	contentLength int64
+	stall         *stall.Detector
+
+	// spoolMu guards spool, so it isn't removed during a write.
+	spoolMu   sync.Mutex
+	spool     *os.File
+	keepSpool bool
+	failed    bool
+
+	recentStats *statCache
+	untrack     func()
+
+	stats   operationStats
+	task    func(*error)
+	observe func(*error)
 
 	tracker leak.Ref
 }
 
-// Info returns the last information about the uploaded object.
+// Info returns the last information about the uploaded object. After a
+// successful Commit it includes the values assigned by the satellite, so no
+// additional StatObject is needed.
 func (upload *Upload) Info() *Object {
 	meta := upload.upload.Meta()
 	if meta != nil {
@@ -166,13 +333,92 @@ func (upload *Upload) Info() *Object {
 // and any error encountered that caused the write to stop early.
 func (upload *Upload) Write(p []byte) (n int, err error) {
 	track := upload.stats.trackWorking()
-	n, err = upload.upload.Write(p)
+	if err := upload.writeSpool(p); err != nil {
+		upload.mu.Lock()
+		upload.failed = true
+		upload.stats.flagFailure(err)
+		track()
+		upload.mu.Unlock()
+		return 0, packageError.New("failed to write spool: %v", err)
+	}
+	if upload.compress != nil {
+		n, err = upload.compress.Write(p)
+	} else {
+		n, err = upload.upload.Write(p)
+	}
 	upload.mu.Lock()
 	upload.stats.bytes += int64(n)
 	upload.stats.flagFailure(err)
+	if err != nil {
+		upload.failed = true
+	}
 	track()
 	upload.mu.Unlock()
-	return n, convertKnownErrors(err, upload.bucket, upload.object.Key)
+	return n, upload.convertError(err)
+}
+
+// writeSpool appends p to the spool file, unless the upload isn't spooled or
+// the spool file was already removed or handed out.
+func (upload *Upload) writeSpool(p []byte) error {
+	upload.spoolMu.Lock()
+	defer upload.spoolMu.Unlock()
+
+	if upload.spool == nil {
+		return nil
+	}
+	_, err := upload.spool.Write(p)
+	return err
+}
+
+// convertError converts err into a known error. Errors caused by a stall are
+// returned as ErrUploadStalled.
+func (upload *Upload) convertError(err error) error {
+	if err != nil && upload.stall != nil {
+		if stallErr := upload.stall.Err(); stallErr != nil {
+			return errwrapf("%w: %v", ErrUploadStalled, stallErr)
+		}
+	}
+	return convertKnownErrors(err, upload.bucket, upload.object.Key)
+}
+
+// ReadFrom implements io.ReaderFrom. It uploads the data read from r until
+// EOF, using larger buffers than io.Copy. It returns the number of bytes
+// uploaded and any error encountered, except io.EOF.
+func (upload *Upload) ReadFrom(r io.Reader) (n int64, err error) {
+	size := int64(readFromBufferSize)
+	if upload.contentLength > 0 && upload.contentLength < size {
+		// one more byte, so the EOF is usually noticed in the same read.
+		size = upload.contentLength + 1
+		if size < readFromMinBufferSize {
+			size = readFromMinBufferSize
+		}
+	}
+	buf := make([]byte, size)
+
+	for {
+		nr, rerr := r.Read(buf)
+		if nr > 0 {
+			nw, werr := upload.Write(buf[:nr])
+			n += int64(nw)
+			if werr != nil {
+				return n, werr
+			}
+		}
+		if nr == len(buf) && len(buf) < readFromBufferSize {
+			// the content length was too small, grow the buffer.
+			size = 2 * int64(len(buf))
+			if size > readFromBufferSize {
+				size = readFromBufferSize
+			}
+			buf = make([]byte, size)
+		}
+		if errors.Is(rerr, io.EOF) {
+			return n, nil
+		}
+		if rerr != nil {
+			return n, rerr
+		}
+	}
 }
 
 // Commit commits data to the store.
@@ -192,17 +438,74 @@ func (upload *Upload) Commit() error {
 	}
 
 	upload.closed = true
+	// the upload is only untracked once it's committed, so Shutdown waits
+	// for the commit in progress.
+	defer upload.stopTracking()
 
-	err := errs.Combine(
-		upload.upload.Commit(),
-		upload.streams.Close(),
-		upload.tracker.Close(),
-	)
+	var err error
+	if upload.compress != nil {
+		err = upload.finishCompression()
+	}
+
+	if err != nil {
+		err = errs.Combine(
+			err,
+			upload.upload.Abort(),
+			upload.streams.Close(),
+			upload.tracker.Close(),
+		)
+	} else {
+		err = errs.Combine(
+			upload.upload.Commit(),
+			upload.streams.Close(),
+			upload.tracker.Close(),
+		)
+	}
 	upload.stats.flagFailure(err)
 	track()
 	upload.emitEvent(false)
+	upload.closeStall()
+	if err != nil {
+		upload.failed = true
+	} else {
+		upload.recentStats.forget(upload.bucket, upload.object.Key)
+	}
+	if err == nil || !upload.keepSpool {
+		upload.removeSpool()
+	}
 
-	return convertKnownErrors(err, upload.bucket, upload.object.Key)
+	return upload.convertError(err)
+}
+
+// stopTracking removes the upload from the operations aborted by
+// Project.Shutdown.
+func (upload *Upload) stopTracking() {
+	if upload.untrack != nil {
+		upload.untrack()
+	}
+}
+
+// closeStall stops the stall detection.
+func (upload *Upload) closeStall() {
+	if upload.stall != nil {
+		upload.stall.Close()
+	}
+}
+
+// finishCompression flushes the compressed data and records the compression
+// scheme in the custom metadata when the data was compressed.
+func (upload *Upload) finishCompression() error {
+	compressed, err := upload.compress.Close()
+	if err != nil {
+		return err
+	}
+	if compressed {
+		if upload.object.Custom == nil {
+			upload.object.Custom = CustomMetadata{}
+		}
+		upload.object.Custom[CompressionMetadataKey] = string(upload.compress.compression)
+	}
+	return nil
 }
 
 // Abort aborts the upload.
@@ -222,6 +525,7 @@ func (upload *Upload) Abort() error {
 	}
 
 	upload.aborted = true
+	defer upload.stopTracking()
 	upload.cancel()
 
 	err := errs.Combine(
@@ -233,6 +537,10 @@ func (upload *Upload) Abort() error {
 	track()
 	upload.stats.flagFailure(err)
 	upload.emitEvent(true)
+	upload.closeStall()
+	if !upload.keepSpool {
+		upload.removeSpool()
+	}
 
 	return convertKnownErrors(err, upload.bucket, upload.object.Key)
 }
@@ -241,6 +549,14 @@ func (upload *Upload) emitEvent(aborted bool) {
 	message, err := upload.stats.err()
 	upload.task(&err)
 
+	callErr := err
+	if aborted && callErr == nil {
+		callErr = context.Canceled
+	}
+	if upload.observe != nil {
+		upload.observe(&callErr)
+	}
+
 	expires := false
 	if upload.upload != nil {
 		meta := upload.upload.Meta()
@@ -270,6 +586,58 @@ func (upload *Upload) emitEvent(aborted bool) {
 	)
 }
 
+// Spool returns the data written to a failed or aborted upload, when
+// UploadOptions.SpoolDir was set. The upload can be retried by copying the
+// returned reader to a new upload. Closing the reader removes the spool file.
+// After Abort or a failed Commit, the data is only available when
+// UploadOptions.KeepSpool was set.
+//
+// Spool can only be called once.
+func (upload *Upload) Spool() (_ io.ReadSeekCloser, err error) {
+	upload.mu.Lock()
+	defer upload.mu.Unlock()
+	upload.spoolMu.Lock()
+	defer upload.spoolMu.Unlock()
+
+	if upload.spool == nil {
+		return nil, packageError.New("upload is not spooled")
+	}
+	if !upload.failed && !upload.aborted {
+		return nil, packageError.New("spool is only available after the upload failed or was aborted")
+	}
+
+	spool := upload.spool
+	upload.spool = nil
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, packageError.Wrap(errs.Combine(err, spool.Close(), os.Remove(spool.Name())))
+	}
+	return &spoolReader{File: spool}, nil
+}
+
+// removeSpool removes the spool file, unless it was handed out by Spool.
+func (upload *Upload) removeSpool() {
+	upload.spoolMu.Lock()
+	defer upload.spoolMu.Unlock()
+
+	if upload.spool == nil {
+		return
+	}
+	_ = upload.spool.Close()
+	_ = os.Remove(upload.spool.Name())
+	upload.spool = nil
+}
+
+// spoolReader reads a spool file and removes it when closed.
+type spoolReader struct {
+	*os.File
+}
+
+// Close closes and removes the spool file.
+func (spool *spoolReader) Close() error {
+	return packageError.Wrap(errs.Combine(spool.File.Close(), os.Remove(spool.File.Name())))
+}
+
 // SetCustomMetadata updates custom metadata to be included with the object.
 // If it is nil, it won't be modified.
 func (upload *Upload) SetCustomMetadata(ctx context.Context, custom CustomMetadata) error {
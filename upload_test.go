@@ -0,0 +1,141 @@
+// Copyright (C) 2024 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package uplink_test
+
+import (
+	"context"
+	"net"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zeebo/errs"
+
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+	"storj.io/common/memory"
+	"storj.io/common/storj"
+	"storj.io/common/testcontext"
+	"storj.io/common/testrand"
+	"storj.io/uplink"
+)
+
+// openOfflineProject opens a project, whose satellite can't be dialed. Dials
+// block until their context is done.
+func openOfflineProject(t *testing.T, ctx *testcontext.Context) *uplink.Project {
+	apiKey, err := macaroon.NewAPIKey(testrand.Bytes(32))
+	require.NoError(t, err)
+
+	rootKey := testrand.Key()
+	encAccess := grant.NewEncryptionAccessWithDefaultKey(&rootKey)
+	encAccess.SetDefaultPathCipher(storj.EncAESGCM)
+
+	serialized, err := (&grant.Access{
+		SatelliteAddress: "12EayRS2V1kEsWESU9QMRseFhdxYxKicsiFmxrsLZHeLUtdps3S@127.0.0.1:7777",
+		APIKey:           apiKey,
+		EncAccess:        encAccess,
+	}).Serialize()
+	require.NoError(t, err)
+
+	access, err := uplink.ParseAccess(serialized)
+	require.NoError(t, err)
+
+	config := uplink.Config{
+		DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+			<-ctx.Done()
+			return nil, errs.New("offline")
+		},
+	}
+
+	project, err := config.OpenProject(ctx, access)
+	require.NoError(t, err)
+	return project
+}
+
+func TestUpload_SpoolShutdown(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	spoolDir := t.TempDir()
+	project := openOfflineProject(t, ctx)
+
+	upload, err := project.UploadObject(ctx, "bucket", "key", &uplink.UploadOptions{
+		SpoolDir: spoolDir,
+	})
+	require.NoError(t, err)
+
+	data := testrand.Bytes(memory.KiB)
+
+	written := make(chan struct{})
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// keep writing after the writes fail, so writes run during the
+		// abort of Shutdown.
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			_, _ = upload.Write(data)
+			if i == 0 {
+				close(written)
+			}
+		}
+	}()
+
+	// shut down while the upload is spooling writes.
+	<-written
+	require.NoError(t, project.Shutdown(ctx))
+	close(stop)
+	wg.Wait()
+
+	require.ErrorIs(t, upload.Commit(), uplink.ErrUploadDone)
+
+	// the spool file is removed by the abort of Shutdown.
+	entries, err := os.ReadDir(spoolDir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestUploadOptions_EncryptionBlockSize(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	project := openOfflineProject(t, ctx)
+	defer ctx.Check(project.Close)
+
+	capabilities := &uplink.Capabilities{LocalSegmentSize: 64 * memory.MiB.Int64()}
+
+	for _, blockSize := range []int32{0, 17, 1024, 64 * memory.MiB.Int32()} {
+		require.NoError(t, capabilities.VerifyUploadOptions(&uplink.UploadOptions{EncryptionBlockSize: blockSize}), blockSize)
+	}
+
+	// the block size must leave room for data after the cipher overhead.
+	for _, blockSize := range []int32{-1, 1, 16, 64*memory.MiB.Int32() + 1} {
+		options := &uplink.UploadOptions{EncryptionBlockSize: blockSize}
+		require.Error(t, capabilities.VerifyUploadOptions(options), blockSize)
+
+		_, err := project.UploadObject(ctx, "bucket", "key", options)
+		require.Error(t, err, blockSize)
+	}
+}
+
+func TestUploadOptions_PieceHash(t *testing.T) {
+	ctx := testcontext.New(t)
+
+	project := openOfflineProject(t, ctx)
+	defer ctx.Check(project.Close)
+
+	for _, hash := range []uplink.PieceHash{uplink.PieceHashDefault, uplink.PieceHashSHA256, uplink.PieceHashBLAKE3} {
+		upload, err := project.UploadObject(ctx, "bucket", "key", &uplink.UploadOptions{PieceHash: hash})
+		require.NoError(t, err, hash)
+		require.NoError(t, upload.Abort())
+	}
+
+	_, err := project.UploadObject(ctx, "bucket", "key", &uplink.UploadOptions{PieceHash: uplink.PieceHashBLAKE3 + 1})
+	require.Error(t, err)
+}